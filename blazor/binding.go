@@ -1,82 +1,273 @@
 package blazor
 
 import (
+	"context"
+	"io"
 	"strings"
 
 	"github.com/a-h/templ"
+	"github.com/gofiber/fiber/v3"
+	"github.com/valyala/fasthttp"
 )
 
+// HeaderScope는 htmx 요청이 어느 컴포넌트 인스턴스에서 왔는지 전달하는 헤더입니다.
+const HeaderScope = "X-Blazor-Scope"
+
 // Field는 특정 입력 요소의 ID와 Name을 관리합니다.
+// Errors에는 직전 요청에서 이 필드에 대해 나온 검증 메시지가 들어 있습니다.
 type Field struct {
-	ID   string
-	Name string
+	ID     string
+	Name   string
+	Errors []string
+
+	key    string
+	path   string // scope를 붙이기 전의 Name
+	scoped bool
+
+	// file, accept, multiple은 File, Files로 만든 파일 입력에만 쓰입니다.
+	file     bool
+	accept   string
+	multiple bool
+
+	// constraints는 Constrain이 validate 규칙에서 옮긴 HTML5 속성입니다.
+	constraints templ.Attributes
 }
 
 // Attrs는 templ에서 <input { field.Attrs()... } /> 형태로 쓸 수 있게 해줍니다.
 func (f Field) Attrs() templ.Attributes {
 	attrs := templ.Attributes{"id": f.ID}
+	for k, v := range f.constraints {
+		attrs[k] = v
+	}
 	if len(f.Name) != 0 {
 		attrs["name"] = f.Name
 	}
+	if f.file {
+		attrs["type"] = "file"
+		if f.accept != "" {
+			attrs["accept"] = f.accept
+		}
+		if f.multiple {
+			attrs["multiple"] = true
+		}
+	}
+	if f.Invalid() {
+		attrs["aria-invalid"] = "true"
+	}
 	return attrs
 }
 
+// Invalid는 필드에 검증 오류가 있는지 알려줍니다.
+func (f Field) Invalid() bool {
+	return len(f.Errors) != 0
+}
+
+// Message는 첫 번째 검증 메시지를 반환합니다.
+func (f Field) Message() string {
+	if len(f.Errors) == 0 {
+		return ""
+	}
+	return f.Errors[0]
+}
+
 // Selector는 CSS 선택자(#id)를 반환합니다.
 func (f Field) Selector() string {
 	return "#" + f.ID
 }
 
+// In은 같은 필드를 다른 인스턴스 scope의 ID로 바꿔 반환합니다.
+func (f Field) In(scope string) Field {
+	if f.key == "" {
+		return f
+	}
+	b := NewBinding(scope)
+	f.ID = b.scopedID(f.key)
+	if f.path != "" {
+		f.Name = scopedName(b.scope, f.path)
+	}
+	f.scoped = b.scope != ""
+	return f
+}
+
+// resolve는 scope 없이 만든 필드를 렌더링 context의 scope에 맞춥니다.
+func (f Field) resolve(ctx context.Context) Field {
+	if f.scoped {
+		return f
+	}
+	return f.In(ScopeFrom(ctx))
+}
+
 // Binding은 특정 영역(네임스페이스) 내의 필드들을 관리합니다.
+// scope가 지정되면 같은 컴포넌트를 여러 번 렌더링해도 ID가 겹치지 않도록 접두사를 붙입니다.
 type Binding struct {
+	scope  string
+	prefix string
+	errors ValidationErrors
+}
+
+func NewBinding(scope string) *Binding {
+	return &Binding{scope: sanitizeScope(scope)}
 }
 
-func NewBinding() *Binding {
-	return &Binding{}
+// BindingFrom은 context에 담긴 인스턴스 scope와 검증 오류로 Binding을 만듭니다.
+func BindingFrom(ctx context.Context) *Binding {
+	b := NewBinding(ScopeFrom(ctx))
+	b.errors = ErrorsFrom(ctx)
+	return b
 }
 
+// Scope는 이 Binding의 인스턴스 scope를 반환합니다.
+func (b *Binding) Scope() string {
+	return b.scope
+}
+
+// Field는 scope가 적용된 ID와 Name을 가진 필드를 만듭니다.
+// Name에는 "scope:"가 붙으므로 한 <form>에 같은 컴포넌트가 여럿 있어도 값이 섞이지 않습니다.
+// bindRequest는 X-Blazor-Scope 헤더의 scope로 접두사를 떼어 바인딩하므로, scope 안의 폼은
+// .Scope(binder.Scope())로 그 헤더를 보내야 합니다.
 func (b *Binding) Field(name string) Field {
-	return Field{ID: name, Name: name}
+	name = b.path(name)
+	key := pathID(name)
+	return Field{ID: b.scopedID(key), Name: scopedName(b.scope, name), Errors: b.errors.Get(name), key: key, path: name, scoped: b.scope != ""}
+}
+
+// Nested는 name 아래의 필드(중첩 구조체, 슬라이스 원소)를 위한 Binding을 만듭니다.
+// 필드 Name은 Fiber 바인더가 읽는 점 표기(address.street, items.0.name)가 됩니다.
+func (b *Binding) Nested(name string) *Binding {
+	return &Binding{scope: b.scope, prefix: b.path(name), errors: b.errors}
+}
+
+// Errors는 이 Binding에 붙은 전체 검증 오류를 반환합니다.
+func (b *Binding) Errors() ValidationErrors {
+	return b.errors
 }
 
 func (b *Binding) ID(name string) Field {
-	return Field{ID: name, Name: ""}
+	key := pathID(b.path(name))
+	return Field{ID: b.scopedID(key), Name: "", key: key, scoped: b.scope != ""}
+}
+
+func (b *Binding) path(name string) string {
+	if b.prefix == "" {
+		return name
+	}
+	return b.prefix + "." + name
 }
 
-// HTMX 관련 속성을 빌드하는 도우미
-type HXAttr struct {
-	attrs templ.Attributes
+// pathID는 점 표기 이름을 CSS 선택자에 그대로 쓸 수 있는 ID로 바꿉니다.
+func pathID(name string) string {
+	return strings.ReplaceAll(name, ".", "-")
 }
 
-func Post(url string) *HXAttr {
-	return &HXAttr{attrs: templ.Attributes{"hx-post": url}}
+func (b *Binding) scopedID(name string) string {
+	if b.scope == "" {
+		return name
+	}
+	return b.scope + "-" + name
 }
 
-func Get(url string) *HXAttr {
-	return &HXAttr{attrs: templ.Attributes{"hx-get": url}}
+// scopeSep은 입력 name에서 scope와 원래 이름을 나눕니다. sanitizeScope가 남기지 않는 문자이므로
+// 서버가 모호함 없이 떼어 낼 수 있습니다.
+const scopeSep = ":"
+
+func scopedName(scope, name string) string {
+	if scope == "" {
+		return name
+	}
+	return scope + scopeSep + name
 }
 
-func Put(url string) *HXAttr {
-	return &HXAttr{attrs: templ.Attributes{"hx-put": url}}
+// unscopeName은 요청 값의 이름을 scope 기준으로 되돌립니다. 이 scope의 값이면 접두사를 떼고,
+// 다른 scope의 값이면 ok가 false입니다. scope가 붙지 않은 이름(_csrf 등)은 그대로 둡니다.
+func unscopeName(scope, key string) (name string, ok bool) {
+	prefix, name, found := strings.Cut(key, scopeSep)
+	if !found || prefix == "" || sanitizeScope(prefix) != prefix {
+		return key, true
+	}
+	return name, prefix == scope
 }
 
-func Patch(url string) *HXAttr {
-	return &HXAttr{attrs: templ.Attributes{"hx-patch": url}}
+// unscopeValues는 values의 이름에서 scope 접두사를 떼고 다른 scope의 값은 버립니다.
+func unscopeValues[V any](scope string, values map[string][]V) {
+	moved := make(map[string][]V)
+	for key, vs := range values {
+		name, ok := unscopeName(scope, key)
+		if ok && name == key {
+			continue
+		}
+		delete(values, key)
+		if ok {
+			moved[name] = append(moved[name], vs...)
+		}
+	}
+	for name, vs := range moved {
+		values[name] = append(values[name], vs...)
+	}
 }
 
-func Delete(url string) *HXAttr {
-	return &HXAttr{attrs: templ.Attributes{"hx-delete": url}}
+// unscopeArgs는 쿼리나 urlencoded 본문에 unscopeValues와 같은 일을 합니다.
+func unscopeArgs(scope string, args *fasthttp.Args) {
+	type pair struct{ key, value string }
+	var kept []pair
+	changed := false
+	for k, v := range args.All() {
+		name, ok := unscopeName(scope, string(k))
+		changed = changed || !ok || name != string(k)
+		if ok {
+			kept = append(kept, pair{name, string(v)})
+		}
+	}
+	if !changed {
+		return
+	}
+	args.Reset()
+	for _, p := range kept {
+		args.Add(p.key, p.value)
+	}
+}
+
+// unscopeRequest는 Fiber 바인더가 읽기 전에 요청 값의 이름을 요청을 보낸 인스턴스 기준으로 되돌립니다.
+func unscopeRequest(c fiber.Ctx, scope string) {
+	unscopeArgs(scope, c.Request().URI().QueryArgs())
+	unscopeArgs(scope, c.Request().PostArgs())
+	if form, err := c.MultipartForm(); err == nil {
+		unscopeValues(scope, form.Value)
+		unscopeValues(scope, form.File)
+	}
+}
+
+func sanitizeScope(scope string) string {
+	return strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9', r == '-', r == '_':
+			return r
+		}
+		return '-'
+	}, scope)
+}
+
+type scopeKey struct{}
+
+// WithScope는 렌더링 context에 인스턴스 scope를 담습니다.
+func WithScope(ctx context.Context, scope string) context.Context {
+	return context.WithValue(ctx, scopeKey{}, sanitizeScope(scope))
 }
 
-func (h *HXAttr) Target(selector string) *HXAttr {
-	h.attrs["hx-target"] = selector
-	return h
+// ScopeFrom은 렌더링 context에 담긴 인스턴스 scope를 반환합니다.
+func ScopeFrom(ctx context.Context) string {
+	scope, _ := ctx.Value(scopeKey{}).(string)
+	return scope
 }
 
-func (h *HXAttr) Include(selectors ...string) *HXAttr {
-	h.attrs["hx-include"] = strings.Join(selectors, ", ")
-	return h
+// ScopeOf는 요청을 보낸 컴포넌트 인스턴스의 scope를 반환합니다.
+func ScopeOf(c fiber.Ctx) string {
+	return sanitizeScope(c.Get(HeaderScope))
 }
 
-func (h *HXAttr) Build() templ.Attributes {
-	return h.attrs
+// Scoped는 content를 주어진 scope 안에서 렌더링합니다.
+// 같은 컴포넌트를 여러 번 배치할 때 각각 다른 scope로 감싸면 됩니다.
+func Scoped(scope string, content templ.Component) templ.Component {
+	return templ.ComponentFunc(func(ctx context.Context, w io.Writer) error {
+		return content.Render(WithScope(ctx, scope), w)
+	})
 }
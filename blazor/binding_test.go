@@ -0,0 +1,139 @@
+package blazor
+
+import (
+	"context"
+	"io"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/a-h/templ"
+	"github.com/gofiber/fiber/v3"
+)
+
+func TestBindingScope(t *testing.T) {
+	b := NewBinding("")
+	if f := b.Field("calc_a"); f.ID != "calc_a" || f.Name != "calc_a" {
+		t.Errorf("Expected unscoped field, got %+v", f)
+	}
+
+	b = NewBinding("left")
+	f := b.Field("calc_a")
+	if f.ID != "left-calc_a" {
+		t.Errorf("Expected scoped ID left-calc_a, got %s", f.ID)
+	}
+	if f.Name != "left:calc_a" {
+		t.Errorf("Expected scoped name left:calc_a, got %s", f.Name)
+	}
+	if moved := f.In("right"); moved.ID != "right-calc_a" || moved.Name != "right:calc_a" {
+		t.Errorf("Expected In to rescope ID and name, got %+v", moved)
+	}
+	if id := b.ID("result"); id.Selector() != "#left-result" {
+		t.Errorf("Expected #left-result, got %s", id.Selector())
+	}
+
+	if s := NewBinding("row 1#x").Scope(); s != "row-1-x" {
+		t.Errorf("Expected sanitized scope row-1-x, got %s", s)
+	}
+}
+
+func TestScopedRender(t *testing.T) {
+	probe := templ.ComponentFunc(func(ctx context.Context, w io.Writer) error {
+		_, err := io.WriteString(w, BindingFrom(ctx).ID("result").ID+";")
+		return err
+	})
+
+	var sb strings.Builder
+	if err := Scoped("a", probe).Render(context.Background(), &sb); err != nil {
+		t.Fatal(err)
+	}
+	if err := Scoped("b", probe).Render(context.Background(), &sb); err != nil {
+		t.Fatal(err)
+	}
+	if sb.String() != "a-result;b-result;" {
+		t.Errorf("Expected distinct IDs per scope, got %s", sb.String())
+	}
+}
+
+func TestHXAttrScope(t *testing.T) {
+	attrs := Post("/calculate").Scope("left").MustBuild()
+	if attrs["hx-headers"] != `{"X-Blazor-Scope":"left"}` {
+		t.Errorf("Unexpected hx-headers: %v", attrs["hx-headers"])
+	}
+
+	attrs = Post("/calculate").Scope("").MustBuild()
+	if _, ok := attrs["hx-headers"]; ok {
+		t.Errorf("Expected no hx-headers for empty scope")
+	}
+}
+
+type scopeRequest struct {
+	A int `form:"a"`
+}
+
+func TestSetRendererResolvesScope(t *testing.T) {
+	app := fiber.New()
+	app.Post("/", SetRenderer(
+		func(data *int) templ.Component {
+			return templ.ComponentFunc(func(ctx context.Context, w io.Writer) error {
+				_, err := io.WriteString(w, BindingFrom(ctx).ID("result").ID)
+				return err
+			})
+		},
+		func(req *scopeRequest) (*int, error) {
+			return &req.A, nil
+		},
+	))
+
+	req := httptest.NewRequest(fiber.MethodPost, "/", strings.NewReader("a=1"))
+	req.Header.Set(fiber.HeaderContentType, fiber.MIMEApplicationForm)
+	req.Header.Set(HeaderScope, "second")
+	resp, err := app.Test(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	body, _ := io.ReadAll(resp.Body)
+	if string(body) != "second-result" {
+		t.Errorf("Expected second-result, got %s", body)
+	}
+}
+
+func TestSetRendererUnscopesNames(t *testing.T) {
+	app := fiber.New()
+	app.Post("/", SetRenderer(
+		func(data *int) templ.Component {
+			return templ.Raw(strconv.Itoa(*data))
+		},
+		func(req *scopeRequest) (*int, error) {
+			return &req.A, nil
+		},
+	))
+
+	post := func(scope, contentType, body string) string {
+		req := httptest.NewRequest(fiber.MethodPost, "/", strings.NewReader(body))
+		req.Header.Set(fiber.HeaderContentType, contentType)
+		req.Header.Set(HeaderScope, scope)
+		resp, err := app.Test(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		got, _ := io.ReadAll(resp.Body)
+		return string(got)
+	}
+
+	// Two instances share one form: each request binds only the values of the instance that sent it.
+	form := "first%3Aa=1&second%3Aa=2"
+	if got := post("first", fiber.MIMEApplicationForm, form); got != "1" {
+		t.Errorf("Expected the first instance's value, got %s", got)
+	}
+	if got := post("second", fiber.MIMEApplicationForm, form); got != "2" {
+		t.Errorf("Expected the second instance's value, got %s", got)
+	}
+
+	multipart := "--b\r\nContent-Disposition: form-data; name=\"first:a\"\r\n\r\n1\r\n" +
+		"--b\r\nContent-Disposition: form-data; name=\"second:a\"\r\n\r\n2\r\n--b--\r\n"
+	if got := post("second", fiber.MIMEMultipartForm+"; boundary=b", multipart); got != "2" {
+		t.Errorf("Expected the second instance's multipart value, got %s", got)
+	}
+}
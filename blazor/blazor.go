@@ -1,8 +1,14 @@
 package blazor
 
 import (
+	"context"
+	"errors"
+	"io"
+	"sync"
+
 	"github.com/a-h/templ"
 	"github.com/gofiber/fiber/v3"
+	"github.com/gofiber/fiber/v3/log"
 	"github.com/gofiber/fiber/v3/middleware/static"
 	"github.com/snowmerak/fiber-blazor/statics"
 )
@@ -11,38 +17,220 @@ const defaultTitle = "Fiber Blazor App"
 const defaultLang = "en"
 
 func InitRender(root templ.Component, lang string, title string) fiber.Handler {
-	if title == "" {
-		title = defaultTitle
-	}
-	if lang == "" {
-		lang = defaultLang
+	return InitLayout(root, Layout{Lang: lang, Title: title})
+}
+
+// InitLayout은 root를 layout으로 감싸 전체 문서로 응답하는 핸들러를 만듭니다.
+// 앞선 핸들러에서 SetTitle, SetDescription으로 라우트별 제목과 설명을 바꿀 수 있습니다.
+func InitLayout(root templ.Component, layout Layout) fiber.Handler {
+	if err := layout.Err(); err != nil {
+		panic(err)
 	}
 	return func(c fiber.Ctx) error {
-		c.Set(fiber.HeaderContentType, fiber.MIMETextHTMLCharsetUTF8)
-		return Page(title, lang, root).Render(c.Context(), c.Res().Response().BodyWriter())
+		return layout.Render(c, root)
 	}
 }
 
+// Page는 기본 레이아웃으로 content를 감싼 문서입니다.
+func Page(title string, lang string, content templ.Component) templ.Component {
+	return Layout{Title: title, Lang: lang}.Component(content)
+}
+
+// Static은 내장 스크립트를 prefix 아래에서 제공합니다.
+// 개발 모드(flazor dev)에서는 브라우저 새로 고침용 SSE 경로도 함께 등록합니다.
 func Static(app *fiber.App, prefix string) {
 	app.Use(prefix, static.New("", static.Config{
 		FS: statics.FS,
 	}))
+	if DevMode() {
+		app.Get(DevReloadPath, devReload)
+	}
+}
+
+// RendererOption은 SetRenderer의 동작을 조정합니다.
+type RendererOption func(*rendererConfig)
+
+type rendererConfig struct {
+	onInvalid func(c fiber.Ctx) templ.Component
+	layout    *Layout
+	maxUpload int64
+}
+
+// OnInvalid는 바인딩이나 검증에 실패했을 때 다시 렌더링할 컴포넌트를 지정합니다.
+// 컴포넌트는 검증 오류가 담긴 context로 렌더링되므로 GetBindingOf*From(ctx)로 만든
+// 바인더의 각 Field에 메시지가 붙습니다. htmx가 응답을 스왑하도록 200으로 응답합니다.
+func OnInvalid(render func(c fiber.Ctx) templ.Component) RendererOption {
+	return func(cfg *rendererConfig) {
+		cfg.onInvalid = render
+	}
+}
+
+// WithLayout은 전체 문서가 필요한 요청에 쓸 레이아웃을 지정합니다. UseLayout보다 우선합니다.
+func WithLayout(layout Layout) RendererOption {
+	if err := layout.Err(); err != nil {
+		panic(err)
+	}
+	return func(cfg *rendererConfig) {
+		cfg.layout = &layout
+	}
 }
 
-func SetRenderer[T, V any](componentFunc func(data *V) templ.Component, transform func(req *T) (*V, error)) fiber.Handler {
+func SetRenderer[T, V any](componentFunc func(data *V) templ.Component, transform func(req *T) (*V, error), opts ...RendererOption) fiber.Handler {
+	return SetResultRenderer(componentFunc, func(req *T) (*Result[V], error) {
+		data, err := transform(req)
+		if err != nil {
+			return nil, err
+		}
+		return Reply(data), nil
+	}, opts...)
+}
+
+// SetConvertedRenderer는 바인딩과 검증을 Binded 구조체로 한 뒤, convert로 원래 구조체로 바꿔
+// transform에 넘기는 SetRenderer입니다. convert에는 flazor가 만든 (*BindedX).ToX를 넘깁니다.
+func SetConvertedRenderer[B, T, V any](componentFunc func(data *V) templ.Component, convert func(req *B) T, transform func(req *T) (*V, error), opts ...RendererOption) fiber.Handler {
+	return SetRenderer(componentFunc, func(req *B) (*V, error) {
+		v := convert(req)
+		return transform(&v)
+	}, opts...)
+}
+
+// SetResultRenderer는 transform이 데이터와 함께 htmx 응답 헤더(HX-Trigger, HX-Redirect 등)를
+// 돌려줄 수 있는 SetRenderer입니다.
+func SetResultRenderer[T, V any](componentFunc func(data *V) templ.Component, transform func(req *T) (*Result[V], error), opts ...RendererOption) fiber.Handler {
+	return newRenderer(componentFunc, func(c fiber.Ctx, req *T) (*Result[V], error) {
+		return transform(req)
+	}, opts)
+}
+
+// newRenderer는 모든 렌더러가 공유하는 CSRF 검사, 바인딩, 검증, 렌더링 과정입니다.
+func newRenderer[T, V any](componentFunc func(data *V) templ.Component, transform func(c fiber.Ctx, req *T) (*Result[V], error), opts []RendererOption) fiber.Handler {
+	var cfg rendererConfig
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+
 	return func(c fiber.Ctx) error {
+		if err := VerifyCSRF(c); err != nil {
+			return err
+		}
+		if err := cfg.checkUploadSize(c); err != nil {
+			return err
+		}
+
 		req := new(T)
-		if err := c.Bind().All(req); err != nil {
-			return fiber.ErrBadRequest
+		if errs, err := bindRequest(c, req); err != nil {
+			return err
+		} else if errs != nil {
+			return cfg.invalid(c, errs)
 		}
-		data, err := transform(req)
+
+		result, err := transform(c, req)
 		if err != nil {
-			return fiber.ErrBadRequest
+			return cfg.fail(c, err)
 		}
 
-		component := componentFunc(data)
+		if result == nil {
+			return nil
+		}
+		if err := result.HX.Apply(c); err != nil {
+			return err
+		}
+		if result.Data == nil {
+			return nil
+		}
+
+		// 요청을 보낸 인스턴스의 scope로 렌더링해야 응답 안의 ID가 원래 컴포넌트와 맞습니다.
+		ctx := WithScope(c.Context(), ScopeOf(c))
+		return cfg.render(c, ctx, componentFunc(result.Data))
+	}
+}
+
+// render는 레이아웃이 있고 전체 문서가 필요한 요청이면 component를 레이아웃으로 감쌉니다.
+func (cfg *rendererConfig) render(c fiber.Ctx, ctx context.Context, component templ.Component) error {
+	layout, ok := LayoutOf(c)
+	if cfg.layout != nil {
+		layout, ok = *cfg.layout, true
+	}
+	if ok {
+		// 같은 URL이 요청 종류에 따라 조각이나 문서를 돌려주므로 캐시가 둘을 섞지 않게 합니다.
+		c.Vary(HeaderHXRequest)
+		if WantsFullPage(c) {
+			component = layout.forRoute(c).Component(component)
+		}
+	}
+
+	c.Set(fiber.HeaderContentType, fiber.MIMETextHTMLCharsetUTF8)
+	return renderChecked(ctx, component, c.Res().Response().BodyWriter())
+}
+
+type renderErrorsKey struct{}
+
+// renderErrors는 렌더링 중에 HXAttr.Attrs가 기록한 오류를 모읍니다.
+type renderErrors struct {
+	mu   sync.Mutex
+	errs []error
+}
+
+func recordRenderError(ctx context.Context, err error) {
+	if re, ok := ctx.Value(renderErrorsKey{}).(*renderErrors); ok {
+		re.mu.Lock()
+		re.errs = append(re.errs, err)
+		re.mu.Unlock()
+	}
+}
+
+// renderChecked는 component를 렌더링하고, 그동안 기록된 속성 오류가 있으면 500 오류를 반환합니다.
+// Fiber는 응답을 모아 두었다가 보내므로 이미 쓴 본문은 오류 응답으로 바뀝니다.
+func renderChecked(ctx context.Context, component templ.Component, w io.Writer) error {
+	re := &renderErrors{}
+	if err := component.Render(context.WithValue(ctx, renderErrorsKey{}, re), w); err != nil {
+		return err
+	}
+	if err := errors.Join(re.errs...); err != nil {
+		return fiber.NewError(fiber.StatusInternalServerError, err.Error())
+	}
+	return nil
+}
+
+// bindRequest는 요청을 req에 바인딩하고 검증합니다.
+// 바인딩이나 검증 오류는 ValidationErrors로, 그 밖의 실패는 400 오류로 반환합니다.
+// 값의 이름에 붙은 scope는 요청을 보낸 인스턴스(ScopeOf) 기준으로 떼어 냅니다.
+func bindRequest(c fiber.Ctx, req any) (ValidationErrors, error) {
+	unscopeRequest(c, ScopeOf(c))
+	if err := c.Bind().All(req); err != nil {
+		errs, ok := bindErrors(err)
+		if !ok {
+			return nil, fiber.NewError(fiber.StatusBadRequest, err.Error())
+		}
+		return errs, nil
+	}
+	if errs := bindMaps(req, requestValues(c)); errs != nil {
+		return errs, nil
+	}
+	return Validate(req), nil
+}
+
+// fail은 transform이 돌려준 오류를 응답으로 바꿉니다.
+// ValidationErrors는 다시 렌더링하고, *fiber.Error는 그대로 보냅니다. 나머지는 DB 오류나 파일 경로가
+// 클라이언트에 드러나지 않도록 서버 로그에만 남기고 본문 없는 400으로 보냅니다.
+func (cfg *rendererConfig) fail(c fiber.Ctx, err error) error {
+	var errs ValidationErrors
+	if errors.As(err, &errs) {
+		return cfg.invalid(c, errs)
+	}
+	var fiberErr *fiber.Error
+	if errors.As(err, &fiberErr) {
+		return fiberErr
+	}
+	log.Errorf("blazor: %s %s: %v", c.Method(), c.Path(), err)
+	return fiber.ErrBadRequest
+}
 
-		c.Set(fiber.HeaderContentType, fiber.MIMETextHTMLCharsetUTF8)
-		return component.Render(c.Context(), c.Res().Response().BodyWriter())
+func (cfg *rendererConfig) invalid(c fiber.Ctx, errs ValidationErrors) error {
+	if cfg.onInvalid == nil {
+		return fiber.NewError(fiber.StatusUnprocessableEntity, errs.Error())
 	}
+
+	ctx := WithErrors(WithScope(c.Context(), ScopeOf(c)), errs)
+	return cfg.render(c, ctx, cfg.onInvalid(c))
 }
@@ -0,0 +1,248 @@
+package blazor
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"reflect"
+	"runtime"
+	"strings"
+	"sync"
+
+	"github.com/a-h/templ"
+	"github.com/gofiber/fiber/v3"
+	"github.com/snowmerak/fiber-blazor/ledis"
+)
+
+const defaultComponentPrefix = "/_blazor"
+
+// Component는 Blazor처럼 상태와 이벤트 핸들러를 가진 컴포넌트입니다.
+// OnInit과 OnParametersSet을 뺀 On으로 시작하는 메서드 중
+// func(ctx context.Context) error 또는 func(ctx context.Context, req *T) error 꼴은
+// Registry.Mount가 POST 라우트로 연결하는 이벤트 핸들러입니다.
+type Component interface {
+	// OnInit은 인스턴스가 처음 만들어질 때 한 번 불립니다.
+	OnInit(ctx context.Context) error
+	// OnParametersSet은 부모가 넘긴 값(필드)이 채워진 뒤 첫 렌더링 전에 불립니다.
+	OnParametersSet(ctx context.Context) error
+	Render() templ.Component
+}
+
+// ComponentBase를 임베드하면 필요한 생명주기 메서드만 구현하면 됩니다.
+type ComponentBase struct{}
+
+func (ComponentBase) OnInit(context.Context) error { return nil }
+
+func (ComponentBase) OnParametersSet(context.Context) error { return nil }
+
+// RenderComponent는 생명주기를 거쳐 컴포넌트를 처음 렌더링합니다.
+// 여러 번 배치할 때는 Scoped로 감싸 인스턴스마다 scope를 나눕니다.
+func RenderComponent(component Component) templ.Component {
+	return templ.ComponentFunc(func(ctx context.Context, w io.Writer) error {
+		if err := component.OnInit(ctx); err != nil {
+			return err
+		}
+		if err := component.OnParametersSet(ctx); err != nil {
+			return err
+		}
+		return component.Render().Render(ctx, w)
+	})
+}
+
+// RegistryConfig는 Registry 설정입니다.
+type RegistryConfig struct {
+	// Prefix는 이벤트 핸들러 라우트의 접두사입니다. 기본값은 "/_blazor"입니다.
+	Prefix string
+	// DB가 있으면 컴포넌트 상태를 세션과 인스턴스마다 저장해 이벤트 사이에 이어 줍니다.
+	// 없으면 이벤트마다 새 인스턴스에서 OnInit부터 시작합니다.
+	DB    *ledis.DistributedMap
+	State StatefulConfig
+	// Options는 이벤트 응답을 렌더링할 때 쓰는 SetRenderer 옵션입니다.
+	Options []RendererOption
+}
+
+// Registry는 컴포넌트의 이벤트 핸들러 메서드를 Fiber 라우트로 연결합니다.
+type Registry struct {
+	router   fiber.Router
+	cfg      RegistryConfig
+	renderer rendererConfig
+}
+
+// NewRegistry는 router에 컴포넌트 라우트를 붙이는 Registry를 만듭니다.
+func NewRegistry(router fiber.Router, cfg RegistryConfig) *Registry {
+	if cfg.Prefix == "" {
+		cfg.Prefix = defaultComponentPrefix
+	}
+	cfg.Prefix = strings.TrimSuffix(cfg.Prefix, "/")
+
+	r := &Registry{router: router, cfg: cfg}
+	for _, opt := range cfg.Options {
+		opt(&r.renderer)
+	}
+	return r
+}
+
+var (
+	contextType = reflect.TypeFor[context.Context]()
+	errorType   = reflect.TypeFor[error]()
+
+	handlerURLsMu sync.RWMutex
+	handlerURLs   = map[string]string{}
+)
+
+// Mount는 factory가 만드는 컴포넌트의 이벤트 핸들러를 POST {Prefix}/{name}/{메서드 이름}으로 연결합니다.
+// factory는 구조체 포인터를 반환해야 하며, 핸들러 시그니처가 맞지 않으면 panic합니다.
+func (r *Registry) Mount(name string, factory func() Component) {
+	typ := reflect.TypeOf(factory())
+	if typ == nil || typ.Kind() != reflect.Pointer || typ.Elem().Kind() != reflect.Struct {
+		panic(fmt.Sprintf("blazor: component %q must be a pointer to a struct, got %v", name, typ))
+	}
+
+	for i := 0; i < typ.NumMethod(); i++ {
+		method := typ.Method(i)
+		if !isEventHandler(method.Name) {
+			continue
+		}
+		argType, err := handlerArg(method.Type)
+		if err != nil {
+			panic(fmt.Sprintf("blazor: %s.%s: %v", name, method.Name, err))
+		}
+
+		url := r.cfg.Prefix + "/" + name + "/" + method.Name
+		r.router.Post(url, r.handler(name, factory, method.Name, argType))
+
+		handlerURLsMu.Lock()
+		handlerURLs[methodKey(typ, method.Name)] = url
+		handlerURLsMu.Unlock()
+	}
+}
+
+func isEventHandler(name string) bool {
+	return strings.HasPrefix(name, "On") && name != "OnInit" && name != "OnParametersSet"
+}
+
+// handlerArg는 핸들러 메서드 타입(첫 인자는 리시버)을 검사하고 요청 구조체 타입을 반환합니다.
+func handlerArg(fn reflect.Type) (reflect.Type, error) {
+	if fn.NumOut() != 1 || fn.Out(0) != errorType {
+		return nil, fmt.Errorf("must return only error")
+	}
+	switch fn.NumIn() {
+	case 2:
+		if fn.In(1) != contextType {
+			return nil, fmt.Errorf("first argument must be context.Context")
+		}
+		return nil, nil
+	case 3:
+		if fn.In(1) != contextType {
+			return nil, fmt.Errorf("first argument must be context.Context")
+		}
+		arg := fn.In(2)
+		if arg.Kind() != reflect.Pointer || arg.Elem().Kind() != reflect.Struct {
+			return nil, fmt.Errorf("second argument must be a pointer to a struct")
+		}
+		return arg.Elem(), nil
+	default:
+		return nil, fmt.Errorf("must take (context.Context) or (context.Context, *T)")
+	}
+}
+
+func (r *Registry) handler(name string, factory func() Component, method string, argType reflect.Type) fiber.Handler {
+	var store *stateStore
+	if r.cfg.DB != nil {
+		s := newStateStore(r.cfg.DB, name, r.cfg.State)
+		store = &s
+	}
+
+	return func(c fiber.Ctx) error {
+		if err := VerifyCSRF(c); err != nil {
+			return err
+		}
+
+		component := factory()
+		ctx := WithScope(c.Context(), ScopeOf(c))
+
+		restored := false
+		if store != nil {
+			var err error
+			if restored, err = store.load(c, component); err != nil {
+				return fiber.NewError(fiber.StatusInternalServerError, err.Error())
+			}
+		}
+		if !restored {
+			if err := component.OnInit(ctx); err != nil {
+				return err
+			}
+			if err := component.OnParametersSet(ctx); err != nil {
+				return err
+			}
+		}
+
+		// 검증에 실패하면 따로 지정하지 않은 한 컴포넌트를 오류와 함께 다시 렌더링합니다.
+		cfg := r.renderer
+		if cfg.onInvalid == nil {
+			cfg.onInvalid = func(fiber.Ctx) templ.Component {
+				return component.Render()
+			}
+		}
+
+		args := []reflect.Value{reflect.ValueOf(ctx)}
+		if argType != nil {
+			req := reflect.New(argType)
+			if errs, err := bindRequest(c, req.Interface()); err != nil {
+				return err
+			} else if errs != nil {
+				return cfg.invalid(c, errs)
+			}
+			args = append(args, req)
+		}
+
+		out := reflect.ValueOf(component).MethodByName(method).Call(args)
+		if err, _ := out[0].Interface().(error); err != nil {
+			return cfg.fail(c, err)
+		}
+
+		if store != nil {
+			if err := store.save(c, component); err != nil {
+				return fiber.NewError(fiber.StatusInternalServerError, err.Error())
+			}
+		}
+		return cfg.render(c, ctx, component.Render())
+	}
+}
+
+// methodKey는 runtime.FuncForPC가 메서드 값에 붙이는 이름과 같은 형식의 키를 만듭니다.
+func methodKey(typ reflect.Type, method string) string {
+	elem := typ.Elem()
+	if _, ok := elem.MethodByName(method); ok {
+		return elem.PkgPath() + "." + elem.Name() + "." + method
+	}
+	return elem.PkgPath() + ".(*" + elem.Name() + ")." + method
+}
+
+// HandlerURL은 Mount된 컴포넌트의 이벤트 핸들러 메서드 값(예: counter.OnClick)이 연결된 URL을 반환합니다.
+func HandlerURL(handler any) (string, bool) {
+	v := reflect.ValueOf(handler)
+	if v.Kind() != reflect.Func {
+		return "", false
+	}
+	fn := runtime.FuncForPC(v.Pointer())
+	if fn == nil {
+		return "", false
+	}
+
+	handlerURLsMu.RLock()
+	defer handlerURLsMu.RUnlock()
+	url, ok := handlerURLs[strings.TrimSuffix(fn.Name(), "-fm")]
+	return url, ok
+}
+
+// Call은 컴포넌트의 이벤트 핸들러로 POST 요청을 보내는 HXAttr을 만듭니다.
+// Mount되지 않은 핸들러면 Build가 오류를 반환합니다.
+func Call(handler any) *HXAttr {
+	url, ok := HandlerURL(handler)
+	h := Post(url)
+	if !ok {
+		h.errs = append(h.errs, fmt.Errorf("hx-post: handler is not mounted on a registry"))
+	}
+	return h
+}
@@ -0,0 +1,142 @@
+package blazor
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/a-h/templ"
+	"github.com/gofiber/fiber/v3"
+	"github.com/snowmerak/fiber-blazor/ledis"
+)
+
+type testCounter struct {
+	ComponentBase
+	Step  int
+	Count int
+}
+
+type testAddRequest struct {
+	N int `form:"n" validate:"min=1"`
+}
+
+func (c *testCounter) OnInit(ctx context.Context) error {
+	c.Step = 1
+	return nil
+}
+
+func (c *testCounter) OnIncrement(ctx context.Context) error {
+	c.Count += c.Step
+	return nil
+}
+
+func (c *testCounter) OnAdd(ctx context.Context, req *testAddRequest) error {
+	c.Count += req.N
+	return nil
+}
+
+func (c *testCounter) OnFail(ctx context.Context) error {
+	return errors.New("boom")
+}
+
+func (c *testCounter) Render() templ.Component {
+	return templ.ComponentFunc(func(ctx context.Context, w io.Writer) error {
+		if errs := ErrorsFrom(ctx); errs != nil {
+			_, err := io.WriteString(w, "invalid: "+errs.Error())
+			return err
+		}
+		_, err := io.WriteString(w, strconv.Itoa(c.Count))
+		return err
+	})
+}
+
+func TestRegistryMount(t *testing.T) {
+	db := ledis.New(0)
+	defer db.Close()
+
+	app := fiber.New()
+	registry := NewRegistry(app, RegistryConfig{DB: db})
+	registry.Mount("counter", func() Component { return &testCounter{} })
+
+	counter := &testCounter{}
+	if url, ok := HandlerURL(counter.OnIncrement); !ok || url != "/_blazor/counter/OnIncrement" {
+		t.Errorf("Unexpected handler URL %q (%v)", url, ok)
+	}
+	if _, ok := HandlerURL(counter.Render); ok {
+		t.Errorf("Expected Render not to be mounted")
+	}
+	if err := Call(counter.OnAdd).Err(); err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+	if err := Call(strings.ToUpper).Err(); err == nil {
+		t.Errorf("Expected an error for an unmounted function")
+	}
+
+	var session string
+	post := func(method, form string) (int, string) {
+		req := httptest.NewRequest(fiber.MethodPost, "/_blazor/counter/"+method, strings.NewReader(form))
+		req.Header.Set(fiber.HeaderContentType, fiber.MIMEApplicationForm)
+		req.Header.Set(HeaderScope, "c1")
+		if session != "" {
+			req.Header.Set(fiber.HeaderCookie, defaultSessionCookie+"="+session)
+		}
+		resp, err := app.Test(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, cookie := range resp.Cookies() {
+			if cookie.Name == defaultSessionCookie {
+				session = cookie.Value
+			}
+		}
+		body, _ := io.ReadAll(resp.Body)
+		return resp.StatusCode, string(body)
+	}
+
+	if _, got := post("OnIncrement", ""); got != "1" {
+		t.Errorf("Expected 1 after OnInit and one increment, got %q", got)
+	}
+	if _, got := post("OnAdd", "n=5"); got != "6" {
+		t.Errorf("Expected the restored state to reach 6, got %q", got)
+	}
+	if code, got := post("OnAdd", "n=0"); code != fiber.StatusOK || got != "invalid: validation failed: n: must be at least 1" {
+		t.Errorf("Unexpected invalid response %d %q", code, got)
+	}
+	if code, got := post("OnFail", ""); code != fiber.StatusBadRequest || strings.Contains(got, "boom") {
+		t.Errorf("Expected a 400 without the handler's error text, got %d %q", code, got)
+	}
+	if _, got := post("OnIncrement", ""); got != "7" {
+		t.Errorf("Expected failed events not to change the state, got %q", got)
+	}
+}
+
+func TestRenderComponent(t *testing.T) {
+	var sb strings.Builder
+	if err := RenderComponent(&testCounter{Count: 3}).Render(context.Background(), &sb); err != nil {
+		t.Fatal(err)
+	}
+	if sb.String() != "3" {
+		t.Errorf("Unexpected render %q", sb.String())
+	}
+}
+
+type badComponent struct {
+	ComponentBase
+}
+
+func (b *badComponent) OnClick(n int) error { return nil }
+
+func (b *badComponent) Render() templ.Component { return templ.NopComponent }
+
+func TestRegistryMountRejectsBadHandlers(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Error("Expected Mount to panic")
+		}
+	}()
+	NewRegistry(fiber.New(), RegistryConfig{}).Mount("bad", func() Component { return &badComponent{} })
+}
@@ -0,0 +1,73 @@
+package blazor
+
+import (
+	"strings"
+
+	"github.com/a-h/templ"
+)
+
+// ValueKind는 validate 규칙을 HTML 속성으로 옮길 때 필요한 필드 값의 종류입니다.
+// min, max가 숫자에서는 값의 범위, 문자열에서는 길이를 뜻하기 때문입니다.
+type ValueKind int
+
+const (
+	// ValueOther는 bool, time.Time, 슬라이스, 파일처럼 required만 옮기는 값입니다.
+	ValueOther ValueKind = iota
+	ValueString
+	ValueInt
+	ValueFloat
+)
+
+// Constrain은 validate 태그의 규칙을 브라우저가 검사하는 HTML5 속성(required, min, max,
+// minlength, maxlength, pattern, step, inputmode)으로 Attrs에 더합니다. flazor가 만든 바인더가 부릅니다.
+// 서버의 검증(Validate)은 그대로이며, HTML로 옮길 수 없는 규칙은 서버에서만 검사됩니다.
+func (f Field) Constrain(kind ValueKind, tag string) Field {
+	attrs := templ.Attributes{}
+	switch kind {
+	case ValueInt:
+		attrs["inputmode"] = "numeric"
+	case ValueFloat:
+		attrs["inputmode"] = "decimal"
+		attrs["step"] = "any"
+	}
+
+	for _, rule := range splitRules(tag) {
+		name, param, _ := strings.Cut(rule, "=")
+		switch {
+		case name == "required":
+			attrs["required"] = true
+		case (name == "min" || name == "max") && (kind == ValueInt || kind == ValueFloat):
+			attrs[name] = param
+		case (name == "min" || name == "minlen") && kind == ValueString:
+			attrs["minlength"] = param
+		case (name == "max" || name == "maxlen") && kind == ValueString:
+			attrs["maxlength"] = param
+		case name == "len" && kind == ValueString:
+			attrs["minlength"] = param
+			attrs["maxlength"] = param
+		case name == "email" && kind == ValueString:
+			attrs["inputmode"] = "email"
+		case name == "regex" && kind == ValueString:
+			if pattern, ok := htmlPattern(param); ok {
+				attrs["pattern"] = pattern
+			}
+		}
+	}
+
+	if len(attrs) != 0 {
+		f.constraints = attrs
+	}
+	return f
+}
+
+// htmlPattern은 Go 정규식을 pattern 속성으로 옮길 수 있는지 봅니다. 브라우저는 패턴을 값 전체에
+// 맞추므로 ^와 $로 앞뒤가 고정된 패턴만 같은 뜻이 되고, (?i) 같은 Go 전용 문법은 JavaScript가 모릅니다.
+func htmlPattern(re string) (string, bool) {
+	if !strings.HasPrefix(re, "^") || !strings.HasSuffix(re, "$") || strings.HasSuffix(re, `\$`) {
+		return "", false
+	}
+	if strings.Contains(re, "|") || strings.Contains(re, "(?") || strings.Contains(re, "[[:") || strings.Contains(re, `\z`) {
+		return "", false
+	}
+	return re[1 : len(re)-1], true
+}
@@ -0,0 +1,41 @@
+package blazor
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/a-h/templ"
+)
+
+func TestFieldConstrain(t *testing.T) {
+	b := NewBinding("")
+	cases := []struct {
+		kind ValueKind
+		tag  string
+		want templ.Attributes
+	}{
+		{ValueString, "required,minlen=2,maxlen=8", templ.Attributes{"required": true, "minlength": "2", "maxlength": "8"}},
+		{ValueString, "min=2,max=8", templ.Attributes{"minlength": "2", "maxlength": "8"}},
+		{ValueString, "len=4", templ.Attributes{"minlength": "4", "maxlength": "4"}},
+		{ValueString, "required,email", templ.Attributes{"required": true, "inputmode": "email"}},
+		{ValueString, "regex=^[a-z]{2,3}$", templ.Attributes{"pattern": "[a-z]{2,3}"}},
+		{ValueString, "regex=[a-z]+", templ.Attributes{}},
+		{ValueString, "regex=^(?i)abc$", templ.Attributes{}},
+		{ValueInt, "min=18,max=130", templ.Attributes{"min": "18", "max": "130", "inputmode": "numeric"}},
+		{ValueFloat, "min=0.5", templ.Attributes{"min": "0.5", "inputmode": "decimal", "step": "any"}},
+		{ValueOther, "required,min=1,noadmin", templ.Attributes{"required": true}},
+	}
+	for _, c := range cases {
+		attrs := b.Field("x").Constrain(c.kind, c.tag).Attrs()
+		delete(attrs, "id")
+		delete(attrs, "name")
+		if !reflect.DeepEqual(attrs, c.want) {
+			t.Errorf("Constrain(%v, %q) = %v, want %v", c.kind, c.tag, attrs, c.want)
+		}
+	}
+
+	f := b.File("avatar_x", "image/png").Constrain(ValueOther, "required,maxsize=2MB")
+	if attrs := f.In("s1").Attrs(); attrs["required"] != true || attrs["type"] != "file" || attrs["id"] != "s1-avatar_x" {
+		t.Errorf("Expected constraints to survive In, got %v", attrs)
+	}
+}
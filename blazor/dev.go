@@ -0,0 +1,50 @@
+package blazor
+
+import (
+	"bufio"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/gofiber/fiber/v3"
+)
+
+// EnvDev는 flazor dev가 앱을 띄울 때 설정하는 환경 변수입니다.
+const EnvDev = "BLAZOR_DEV"
+
+// DevReloadPath는 개발 모드에서 브라우저가 새로 고침 신호를 받는 SSE 경로입니다.
+const DevReloadPath = "/_blazor/dev/reload"
+
+// devBuildID는 프로세스마다 달라서, 재시작한 서버에 다시 연결한 브라우저가 새로 고침할 때를 알 수 있습니다.
+var devBuildID = strconv.FormatInt(time.Now().UnixNano(), 36)
+
+// DevMode는 flazor dev 아래에서 실행 중인지 알려줍니다.
+func DevMode() bool {
+	return os.Getenv(EnvDev) != ""
+}
+
+// devReload는 연결마다 빌드 ID를 보내고 연결을 유지합니다.
+// 서버가 재시작되면 EventSource가 다시 연결해 다른 ID를 받고 페이지를 새로 고칩니다.
+func devReload(c fiber.Ctx) error {
+	c.Set(fiber.HeaderContentType, "text/event-stream")
+	c.Set(fiber.HeaderCacheControl, "no-cache")
+	c.Set(fiber.HeaderConnection, "keep-alive")
+
+	return c.SendStreamWriter(func(w *bufio.Writer) {
+		// 재시작을 빨리 알아채도록 재연결 간격을 줄입니다.
+		w.WriteString("retry: 300\n")
+		writeSSE(w, "build", devBuildID)
+		if w.Flush() != nil {
+			return
+		}
+
+		heartbeat := time.NewTicker(defaultSSEHeartbeat)
+		defer heartbeat.Stop()
+		for range heartbeat.C {
+			w.WriteString(": ping\n\n")
+			if w.Flush() != nil {
+				return
+			}
+		}
+	})
+}
@@ -0,0 +1,209 @@
+package blazor
+
+import (
+	"context"
+	"encoding"
+	"fmt"
+	"io"
+	"reflect"
+	"strconv"
+	"time"
+
+	"github.com/a-h/templ"
+	"github.com/gofiber/fiber/v3/binder"
+)
+
+// InputType은 생성된 폼이 필드마다 고르는 입력 종류입니다.
+type InputType string
+
+const (
+	InputText     InputType = "text"
+	InputEmail    InputType = "email"
+	InputNumber   InputType = "number"
+	InputCheckbox InputType = "checkbox"
+	InputDateTime InputType = "datetime-local"
+	InputFile     InputType = "file"
+	// InputSelect는 <input> 대신 Options로 <select>를 그립니다.
+	InputSelect InputType = "select"
+)
+
+// SelectOption은 <select>의 선택지 하나입니다.
+type SelectOption struct {
+	Value string
+	Label string
+}
+
+// Option은 열거형 상수 v의 선택지를 만듭니다. Value는 바인더가 읽는 값, Label은 fmt.Sprint(v)라서
+// String 메서드가 있으면 그 결과가 보입니다.
+func Option(v any) SelectOption {
+	return SelectOption{Value: FormValue(v), Label: fmt.Sprint(v)}
+}
+
+// FormInput은 생성된 Form 컴포넌트의 필드 하나입니다. WithInput으로 마크업을 바꿀 때 받는 값입니다.
+type FormInput struct {
+	// Name은 Go 구조체의 필드 이름입니다.
+	Name    string
+	Label   string
+	Type    InputType
+	Field   Field
+	Value   string
+	Options []SelectOption
+	// Attrs는 입력 요소에 더할 속성입니다. Field.Attrs()보다 우선합니다.
+	Attrs templ.Attributes
+}
+
+// InputAttrs는 입력 요소(<input>이나 <select>)에 펼칠 속성을 만듭니다.
+func (in FormInput) InputAttrs() templ.Attributes {
+	attrs := in.Field.Attrs()
+	for k, v := range in.Attrs {
+		attrs[k] = v
+	}
+	switch in.Type {
+	case InputSelect, InputFile:
+	case InputCheckbox:
+		attrs["type"] = string(InputCheckbox)
+		attrs["value"] = "true"
+		if in.Value == "true" {
+			attrs["checked"] = true
+		}
+	default:
+		attrs["type"] = string(in.Type)
+		if in.Value != "" {
+			attrs["value"] = in.Value
+		}
+	}
+	return attrs
+}
+
+// FormOption은 생성된 Form 컴포넌트의 마크업을 바꿉니다.
+type FormOption func(*formConfig)
+
+type formConfig struct {
+	input  func(in FormInput) templ.Component
+	submit templ.Component
+	attrs  templ.Attributes
+}
+
+// WithInput은 필드마다 쓸 마크업을 지정합니다. 일부 필드만 바꾸려면 나머지는 DefaultInput(in)을 돌려줍니다.
+func WithInput(render func(in FormInput) templ.Component) FormOption {
+	return func(cfg *formConfig) {
+		cfg.input = render
+	}
+}
+
+// WithSubmit은 기본 제출 버튼 대신 쓸 컴포넌트를 지정합니다.
+func WithSubmit(submit templ.Component) FormOption {
+	return func(cfg *formConfig) {
+		cfg.submit = submit
+	}
+}
+
+// WithFormAttrs는 <form> 요소에 속성(class 등)을 더합니다. htmx 속성은 submit이 정합니다.
+func WithFormAttrs(attrs templ.Attributes) FormOption {
+	return func(cfg *formConfig) {
+		cfg.attrs = attrs
+	}
+}
+
+// Form은 inputs를 그리는 <form>입니다. flazor가 //blazor:form 구조체마다 만드는 Form* 컴포넌트가 씁니다.
+// 파일 필드가 있으면 submit에 multipart 인코딩을 지정합니다. submit은 Attrs(ctx)로 펼치므로
+// 속성이 잘못되었으면 다른 템플릿처럼 blazor 렌더러가 500으로 응답합니다.
+func Form(submit *HXAttr, inputs []FormInput, opts ...FormOption) templ.Component {
+	cfg := formConfig{input: DefaultInput, submit: defaultSubmit()}
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+	for _, in := range inputs {
+		if in.Type == InputFile {
+			// 여러 폼이 나눠 쓰는 submit을 바꾸지 않도록 복사본에 인코딩을 더합니다.
+			submit = submit.clone().Encoding(EncodingMultipart)
+			break
+		}
+	}
+
+	return templ.ComponentFunc(func(ctx context.Context, w io.Writer) error {
+		hx := submit.Attrs(ctx)
+		attrs := templ.Attributes{"class": "blazor-form"}
+		for k, v := range cfg.attrs {
+			attrs[k] = v
+		}
+		for k, v := range hx {
+			attrs[k] = v
+		}
+		return formView(attrs, inputs, cfg).Render(ctx, w)
+	})
+}
+
+// datetimeLocal은 <input type="datetime-local">이 주고받는 형식입니다.
+const datetimeLocal = "2006-01-02T15:04"
+
+// FormValue는 필드 값을 입력 요소의 value로 바꿉니다. time.Time은 datetime-local 형식(UTC)으로,
+// encoding.TextMarshaler는 MarshalText로, 나머지는 바인더가 다시 읽을 수 있는 문자열로 씁니다.
+func FormValue(v any) string {
+	rv := reflect.ValueOf(v)
+	for rv.Kind() == reflect.Pointer {
+		if rv.IsNil() {
+			return ""
+		}
+		rv = rv.Elem()
+	}
+	if !rv.IsValid() {
+		return ""
+	}
+
+	switch v := rv.Interface().(type) {
+	case time.Time:
+		if v.IsZero() {
+			return ""
+		}
+		v = v.UTC()
+		if v.Second() != 0 {
+			return v.Format(datetimeLocal + ":05")
+		}
+		return v.Format(datetimeLocal)
+	case encoding.TextMarshaler:
+		text, err := v.MarshalText()
+		if err != nil {
+			return ""
+		}
+		return string(text)
+	}
+
+	switch rv.Kind() {
+	case reflect.String:
+		return rv.String()
+	case reflect.Bool:
+		return strconv.FormatBool(rv.Bool())
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return strconv.FormatInt(rv.Int(), 10)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return strconv.FormatUint(rv.Uint(), 10)
+	case reflect.Float32, reflect.Float64:
+		return strconv.FormatFloat(rv.Float(), 'f', -1, rv.Type().Bits())
+	}
+	return fmt.Sprint(rv.Interface())
+}
+
+// TimeParser는 time.Time 필드가 RFC 3339 외에 datetime-local(2006-01-02T15:04)과 date(2006-01-02) 값도
+// 받게 하는 Fiber 변환기입니다. 시간대가 없는 값은 UTC로 읽습니다. RegisterTimeParser로 등록합니다.
+var TimeParser = binder.ParserType{
+	CustomType: time.Time{},
+	Converter: func(s string) reflect.Value {
+		for _, layout := range []string{time.RFC3339Nano, datetimeLocal + ":05", datetimeLocal, time.DateOnly} {
+			if t, err := time.Parse(layout, s); err == nil {
+				return reflect.ValueOf(t)
+			}
+		}
+		return reflect.Value{}
+	},
+}
+
+// RegisterTimeParser는 Fiber의 기본 설정에 TimeParser와 types를 더해 binder.SetParserDecoder를 부릅니다.
+// Fiber의 디코더는 프로세스 전체에 하나이므로, 생성된 폼의 datetime-local 입력을 받는 앱이 main에서 한 번 부릅니다.
+func RegisterTimeParser(types ...binder.ParserType) {
+	binder.SetParserDecoder(binder.ParserConfig{
+		IgnoreUnknownKeys: true,
+		ZeroEmpty:         true,
+		ParserType:        append([]binder.ParserType{TimeParser}, types...),
+	})
+}
@@ -0,0 +1,149 @@
+package blazor
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/a-h/templ"
+	"github.com/gofiber/fiber/v3"
+)
+
+type level int
+
+func (l level) String() string { return fmt.Sprintf("Level %d", int(l)) }
+
+func TestFormValue(t *testing.T) {
+	n := 3
+	cases := []struct {
+		in   any
+		want string
+	}{
+		{"kim", "kim"},
+		{42, "42"},
+		{&n, "3"},
+		{(*int)(nil), ""},
+		{1.5, "1.5"},
+		{true, "true"},
+		{level(2), "2"},
+		{time.Date(2026, 3, 4, 5, 6, 0, 0, time.UTC), "2026-03-04T05:06"},
+		{time.Date(2026, 3, 4, 5, 6, 7, 0, time.UTC), "2026-03-04T05:06:07"},
+		{time.Time{}, ""},
+	}
+	for _, c := range cases {
+		if got := FormValue(c.in); got != c.want {
+			t.Errorf("FormValue(%#v) = %q, want %q", c.in, got, c.want)
+		}
+	}
+	if o := Option(level(1)); o.Value != "1" || o.Label != "Level 1" {
+		t.Errorf("Unexpected option: %+v", o)
+	}
+}
+
+func TestForm(t *testing.T) {
+	b := NewBinding("")
+	inputs := []FormInput{
+		{Name: "Title", Label: "Title", Type: InputText, Field: b.Field("title_x"), Value: "hi"},
+		{Name: "Done", Label: "Done", Type: InputCheckbox, Field: b.Field("done_x"), Value: "true"},
+		{Name: "Level", Label: "Level", Type: InputSelect, Field: b.Field("level_x"), Value: "1",
+			Options: []SelectOption{Option(level(0)), Option(level(1))}},
+	}
+
+	var sb strings.Builder
+	if err := Form(Post("/save").Target("#out"), inputs).Render(context.Background(), &sb); err != nil {
+		t.Fatal(err)
+	}
+	html := sb.String()
+	for _, want := range []string{
+		`<form class="blazor-form" hx-post="/save" hx-target="#out">`,
+		`<label for="title_x">Title</label> <input id="title_x" name="title_x" type="text" value="hi">`,
+		`<input checked id="done_x" name="done_x" type="checkbox" value="true">`,
+		`<option value="1" selected>Level 1</option>`,
+		`<button type="submit">Submit</button>`,
+	} {
+		if !strings.Contains(html, want) {
+			t.Errorf("Expected %s in %s", want, html)
+		}
+	}
+
+	sb.Reset()
+	custom := WithInput(func(in FormInput) templ.Component {
+		if in.Name == "Title" {
+			return templ.Raw("<textarea></textarea>")
+		}
+		return DefaultInput(in)
+	})
+	inputs = append(inputs, FormInput{Name: "Photo", Label: "Photo", Type: InputFile, Field: b.File("photo_x")})
+	submit := Post("/save")
+	err := Form(submit, inputs, custom, WithSubmit(templ.Raw("<button>Save</button>")), WithFormAttrs(templ.Attributes{"class": "grid"})).
+		Render(context.Background(), &sb)
+	if err != nil {
+		t.Fatal(err)
+	}
+	html = sb.String()
+	for _, want := range []string{`class="grid"`, `hx-encoding="multipart/form-data"`, "<textarea></textarea>", "<button>Save</button>", `type="file"`} {
+		if !strings.Contains(html, want) {
+			t.Errorf("Expected %s in %s", want, html)
+		}
+	}
+	if strings.Contains(html, `name="title_x"`) || strings.Contains(html, "Submit") {
+		t.Errorf("Expected the overrides to replace the defaults, got %s", html)
+	}
+	if attrs := submit.MustBuild(); attrs["hx-encoding"] != nil {
+		t.Errorf("Expected the shared submit attribute to stay unchanged, got %v", attrs)
+	}
+
+	// An invalid submit is a programmer error: the renderer answers 500 like any other Attrs(ctx).
+	sb.Reset()
+	err = renderChecked(context.Background(), Form(Post("/save").Confirm(""), inputs[:1]), &sb)
+	if fiberErr, ok := err.(*fiber.Error); !ok || fiberErr.Code != fiber.StatusInternalServerError {
+		t.Errorf("Expected a 500 for an invalid submit, got %v", err)
+	}
+}
+
+type scheduleRequest struct {
+	At time.Time  `form:"at_x"`
+	On *time.Time `form:"on_x"`
+}
+
+func TestTimeParser(t *testing.T) {
+	RegisterTimeParser()
+	app := fiber.New()
+	app.Post("/", SetRenderer(
+		func(data *string) templ.Component {
+			return templ.Raw(*data)
+		},
+		func(req *scheduleRequest) (*string, error) {
+			out := req.At.Format(time.RFC3339)
+			if req.On != nil {
+				out += " " + req.On.Format(time.RFC3339)
+			}
+			return &out, nil
+		},
+	))
+
+	post := func(body string) (int, string) {
+		req := httptest.NewRequest(fiber.MethodPost, "/", strings.NewReader(body))
+		req.Header.Set(fiber.HeaderContentType, fiber.MIMEApplicationForm)
+		resp, err := app.Test(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		b, _ := io.ReadAll(resp.Body)
+		return resp.StatusCode, string(b)
+	}
+
+	if status, body := post("at_x=2026-03-04T05:06&on_x=2026-03-05"); status != fiber.StatusOK || body != "2026-03-04T05:06:00Z 2026-03-05T00:00:00Z" {
+		t.Errorf("Expected datetime-local and date values to bind, got %d %s", status, body)
+	}
+	if _, body := post("at_x=2026-03-04T05:06:00%2B09:00"); body != "2026-03-04T05:06:00+09:00" {
+		t.Errorf("Expected RFC 3339 to keep working, got %s", body)
+	}
+	if status, _ := post("at_x=tomorrow"); status != fiber.StatusUnprocessableEntity {
+		t.Errorf("Expected an invalid time to fail binding, got %d", status)
+	}
+}
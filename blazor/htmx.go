@@ -0,0 +1,558 @@
+package blazor
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/a-h/templ"
+)
+
+// HTMX 관련 속성을 빌드하는 도우미
+// 잘못된 값은 모아 두었다가 Build 시점에 한꺼번에 보고합니다.
+// 템플릿에서는 Attrs(ctx)로 펼칩니다.
+type HXAttr struct {
+	attrs   templ.Attributes
+	headers map[string]string
+	errs    []error
+}
+
+func Post(url string) *HXAttr {
+	return &HXAttr{attrs: templ.Attributes{"hx-post": url}}
+}
+
+func Get(url string) *HXAttr {
+	return &HXAttr{attrs: templ.Attributes{"hx-get": url}}
+}
+
+func Put(url string) *HXAttr {
+	return &HXAttr{attrs: templ.Attributes{"hx-put": url}}
+}
+
+func Patch(url string) *HXAttr {
+	return &HXAttr{attrs: templ.Attributes{"hx-patch": url}}
+}
+
+func Delete(url string) *HXAttr {
+	return &HXAttr{attrs: templ.Attributes{"hx-delete": url}}
+}
+
+func (h *HXAttr) Target(selector string) *HXAttr {
+	h.attrs["hx-target"] = selector
+	return h
+}
+
+func (h *HXAttr) Include(selectors ...string) *HXAttr {
+	h.attrs["hx-include"] = strings.Join(selectors, ", ")
+	return h
+}
+
+// Scope는 요청에 컴포넌트 인스턴스 scope를 실어 보냅니다.
+func (h *HXAttr) Scope(scope string) *HXAttr {
+	if scope == "" {
+		return h
+	}
+	return h.header(HeaderScope, scope)
+}
+
+// SwapStyle은 hx-swap의 스왑 방식입니다.
+type SwapStyle string
+
+const (
+	SwapInnerHTML   SwapStyle = "innerHTML"
+	SwapOuterHTML   SwapStyle = "outerHTML"
+	SwapTextContent SwapStyle = "textContent"
+	SwapBeforeBegin SwapStyle = "beforebegin"
+	SwapAfterBegin  SwapStyle = "afterbegin"
+	SwapBeforeEnd   SwapStyle = "beforeend"
+	SwapAfterEnd    SwapStyle = "afterend"
+	SwapDelete      SwapStyle = "delete"
+	SwapNone        SwapStyle = "none"
+)
+
+func (s SwapStyle) valid() bool {
+	switch s {
+	case SwapInnerHTML, SwapOuterHTML, SwapTextContent, SwapBeforeBegin, SwapAfterBegin,
+		SwapBeforeEnd, SwapAfterEnd, SwapDelete, SwapNone:
+		return true
+	}
+	return false
+}
+
+// ScrollPosition은 scroll/show 수식어의 위치입니다.
+type ScrollPosition string
+
+const (
+	ScrollTop    ScrollPosition = "top"
+	ScrollBottom ScrollPosition = "bottom"
+)
+
+// SwapModifier는 hx-swap 뒤에 붙는 수식어입니다.
+type SwapModifier struct {
+	value string
+	err   error
+}
+
+// SwapDelay는 응답을 받은 뒤 스왑까지 기다릴 시간입니다.
+func SwapDelay(d time.Duration) SwapModifier {
+	return durationModifier("swap", d)
+}
+
+// Settle은 스왑 후 settle 단계까지 기다릴 시간입니다.
+func Settle(d time.Duration) SwapModifier {
+	return durationModifier("settle", d)
+}
+
+// Transition은 View Transitions API로 스왑합니다.
+func Transition() SwapModifier {
+	return SwapModifier{value: "transition:true"}
+}
+
+// IgnoreTitle은 응답의 <title>을 무시합니다.
+func IgnoreTitle() SwapModifier {
+	return SwapModifier{value: "ignoreTitle:true"}
+}
+
+// FocusScroll은 포커스된 요소로 스크롤할지 정합니다.
+func FocusScroll(enabled bool) SwapModifier {
+	return SwapModifier{value: fmt.Sprintf("focus-scroll:%t", enabled)}
+}
+
+// Scroll은 스왑 후 대상(또는 selector) 요소를 스크롤합니다.
+func Scroll(pos ScrollPosition, selector ...string) SwapModifier {
+	return positionModifier("scroll", pos, selector)
+}
+
+// Show는 스왑 후 대상(또는 selector) 요소가 보이도록 스크롤합니다.
+func Show(pos ScrollPosition, selector ...string) SwapModifier {
+	return positionModifier("show", pos, selector)
+}
+
+func durationModifier(name string, d time.Duration) SwapModifier {
+	value, err := formatDuration(d)
+	if err != nil {
+		return SwapModifier{err: fmt.Errorf("%s: %w", name, err)}
+	}
+	return SwapModifier{value: name + ":" + value}
+}
+
+func positionModifier(name string, pos ScrollPosition, selector []string) SwapModifier {
+	if pos != ScrollTop && pos != ScrollBottom {
+		return SwapModifier{err: fmt.Errorf("%s: unknown position %q", name, pos)}
+	}
+	switch len(selector) {
+	case 0:
+		return SwapModifier{value: name + ":" + string(pos)}
+	case 1:
+		if err := checkSelector(selector[0]); err != nil {
+			return SwapModifier{err: fmt.Errorf("%s: %w", name, err)}
+		}
+		return SwapModifier{value: name + ":" + selector[0] + ":" + string(pos)}
+	}
+	return SwapModifier{err: fmt.Errorf("%s: at most one selector is allowed", name)}
+}
+
+// Swap은 hx-swap을 설정합니다.
+func (h *HXAttr) Swap(style SwapStyle, modifiers ...SwapModifier) *HXAttr {
+	value, err := buildSwap(style, modifiers)
+	if err != nil {
+		return h.fail(fmt.Errorf("hx-swap: %w", err))
+	}
+	h.attrs["hx-swap"] = value
+	return h
+}
+
+func buildSwap(style SwapStyle, modifiers []SwapModifier) (string, error) {
+	if !style.valid() {
+		return "", fmt.Errorf("unknown style %q", style)
+	}
+	parts := []string{string(style)}
+	for _, m := range modifiers {
+		if m.err != nil {
+			return "", m.err
+		}
+		parts = append(parts, m.value)
+	}
+	return strings.Join(parts, " "), nil
+}
+
+// QueueStrategy는 trigger의 queue 수식어 값입니다.
+type QueueStrategy string
+
+const (
+	QueueFirst QueueStrategy = "first"
+	QueueLast  QueueStrategy = "last"
+	QueueAll   QueueStrategy = "all"
+	QueueNone  QueueStrategy = "none"
+)
+
+// TriggerSpec은 hx-trigger에 들어갈 이벤트 하나를 기술합니다.
+type TriggerSpec struct {
+	event     string
+	filter    string
+	modifiers []string
+	err       error
+}
+
+// On은 이벤트 이름으로 trigger를 시작합니다.
+func On(event string) *TriggerSpec {
+	t := &TriggerSpec{event: event}
+	if event == "" || strings.ContainsAny(event, " ,[]") {
+		t.err = fmt.Errorf("invalid event name %q", event)
+	}
+	return t
+}
+
+// Every는 주기적으로 요청을 보내는 polling trigger입니다.
+func Every(d time.Duration) *TriggerSpec {
+	t := &TriggerSpec{}
+	value, err := formatDuration(d)
+	if err == nil && d == 0 {
+		err = errors.New("interval must be positive")
+	}
+	if err != nil {
+		t.err = fmt.Errorf("every: %w", err)
+		return t
+	}
+	t.event = "every " + value
+	return t
+}
+
+// Filter는 JavaScript 조건식([expr])이 참일 때만 trigger합니다.
+func (t *TriggerSpec) Filter(expr string) *TriggerSpec {
+	t.filter = expr
+	return t
+}
+
+// Changed는 요소의 값이 바뀌었을 때만 trigger합니다.
+func (t *TriggerSpec) Changed() *TriggerSpec {
+	return t.modifier("changed")
+}
+
+// Once는 한 번만 trigger합니다.
+func (t *TriggerSpec) Once() *TriggerSpec {
+	return t.modifier("once")
+}
+
+// Consume은 이벤트가 부모 요소로 전파되지 않게 합니다.
+func (t *TriggerSpec) Consume() *TriggerSpec {
+	return t.modifier("consume")
+}
+
+// Delay는 마지막 이벤트 후 d만큼 기다렸다가 요청합니다.
+func (t *TriggerSpec) Delay(d time.Duration) *TriggerSpec {
+	return t.durationModifier("delay", d)
+}
+
+// Throttle은 d 동안 추가 이벤트를 무시합니다.
+func (t *TriggerSpec) Throttle(d time.Duration) *TriggerSpec {
+	return t.durationModifier("throttle", d)
+}
+
+// From은 다른 요소(document, window, closest ... 등 확장 선택자 포함)의 이벤트를 듣습니다.
+func (t *TriggerSpec) From(selector string) *TriggerSpec {
+	if err := checkSelector(selector); err != nil {
+		t.setErr(fmt.Errorf("from: %w", err))
+		return t
+	}
+	return t.modifier("from:" + wrapSelector(selector))
+}
+
+// Target은 이벤트 대상이 selector와 일치할 때만 trigger합니다.
+func (t *TriggerSpec) Target(selector string) *TriggerSpec {
+	if err := checkSelector(selector); err != nil {
+		t.setErr(fmt.Errorf("target: %w", err))
+		return t
+	}
+	return t.modifier("target:" + wrapSelector(selector))
+}
+
+// wrapSelector는 공백이 있는 선택자를 htmx가 한 덩어리로 읽도록 괄호로 감쌉니다.
+func wrapSelector(selector string) string {
+	if strings.ContainsAny(selector, " \t") {
+		return "(" + selector + ")"
+	}
+	return selector
+}
+
+// Queue는 요청이 진행 중일 때 들어온 이벤트의 처리 방식을 정합니다.
+func (t *TriggerSpec) Queue(strategy QueueStrategy) *TriggerSpec {
+	switch strategy {
+	case QueueFirst, QueueLast, QueueAll, QueueNone:
+		return t.modifier("queue:" + string(strategy))
+	}
+	t.setErr(fmt.Errorf("queue: unknown strategy %q", strategy))
+	return t
+}
+
+func (t *TriggerSpec) modifier(m string) *TriggerSpec {
+	t.modifiers = append(t.modifiers, m)
+	return t
+}
+
+func (t *TriggerSpec) durationModifier(name string, d time.Duration) *TriggerSpec {
+	value, err := formatDuration(d)
+	if err != nil {
+		t.setErr(fmt.Errorf("%s: %w", name, err))
+		return t
+	}
+	return t.modifier(name + ":" + value)
+}
+
+func (t *TriggerSpec) setErr(err error) {
+	if t.err == nil {
+		t.err = err
+	}
+}
+
+func (t *TriggerSpec) String() string {
+	var sb strings.Builder
+	sb.WriteString(t.event)
+	if t.filter != "" {
+		sb.WriteString("[" + t.filter + "]")
+	}
+	for _, m := range t.modifiers {
+		sb.WriteString(" " + m)
+	}
+	return sb.String()
+}
+
+// Trigger는 hx-trigger를 설정합니다. 여러 trigger는 쉼표로 이어집니다.
+func (h *HXAttr) Trigger(triggers ...*TriggerSpec) *HXAttr {
+	if len(triggers) == 0 {
+		return h.fail(errors.New("hx-trigger: at least one trigger is required"))
+	}
+	parts := make([]string, 0, len(triggers))
+	for _, t := range triggers {
+		if t.err != nil {
+			return h.fail(fmt.Errorf("hx-trigger: %w", t.err))
+		}
+		parts = append(parts, t.String())
+	}
+	h.attrs["hx-trigger"] = strings.Join(parts, ", ")
+	return h
+}
+
+// Vals는 v를 JSON으로 인코딩해 hx-vals로 설정합니다.
+func (h *HXAttr) Vals(v any) *HXAttr {
+	encoded, err := json.Marshal(v)
+	if err != nil {
+		return h.fail(fmt.Errorf("hx-vals: %w", err))
+	}
+	if len(encoded) == 0 || encoded[0] != '{' {
+		return h.fail(fmt.Errorf("hx-vals: value must encode to a JSON object, got %s", encoded))
+	}
+	h.attrs["hx-vals"] = string(encoded)
+	return h
+}
+
+// Confirm은 요청 전에 확인 대화상자를 띄웁니다.
+func (h *HXAttr) Confirm(message string) *HXAttr {
+	if message == "" {
+		return h.fail(errors.New("hx-confirm: message is empty"))
+	}
+	h.attrs["hx-confirm"] = message
+	return h
+}
+
+// Indicator는 요청 중에 htmx-request 클래스를 받을 요소를 지정합니다.
+func (h *HXAttr) Indicator(selector string) *HXAttr {
+	return h.selectorAttr("hx-indicator", selector)
+}
+
+// PushURL은 요청 URL을 브라우저 히스토리에 넣을지 정합니다.
+func (h *HXAttr) PushURL(enabled bool) *HXAttr {
+	h.attrs["hx-push-url"] = fmt.Sprintf("%t", enabled)
+	return h
+}
+
+// PushURLTo는 지정한 URL을 브라우저 히스토리에 넣습니다.
+func (h *HXAttr) PushURLTo(url string) *HXAttr {
+	if url == "" {
+		return h.fail(errors.New("hx-push-url: url is empty"))
+	}
+	h.attrs["hx-push-url"] = url
+	return h
+}
+
+// Select는 응답 중 selector에 해당하는 부분만 스왑합니다.
+func (h *HXAttr) Select(selector string) *HXAttr {
+	return h.selectorAttr("hx-select", selector)
+}
+
+// SyncStrategy는 hx-sync의 동기화 전략입니다.
+type SyncStrategy string
+
+const (
+	SyncDrop       SyncStrategy = "drop"
+	SyncAbort      SyncStrategy = "abort"
+	SyncReplace    SyncStrategy = "replace"
+	SyncQueue      SyncStrategy = "queue"
+	SyncQueueFirst SyncStrategy = "queue first"
+	SyncQueueLast  SyncStrategy = "queue last"
+	SyncQueueAll   SyncStrategy = "queue all"
+)
+
+// Sync는 selector 요소의 요청들과 이 요청을 동기화합니다.
+func (h *HXAttr) Sync(selector string, strategy SyncStrategy) *HXAttr {
+	if err := checkSelector(selector); err != nil {
+		return h.fail(fmt.Errorf("hx-sync: %w", err))
+	}
+	switch strategy {
+	case SyncDrop, SyncAbort, SyncReplace, SyncQueue, SyncQueueFirst, SyncQueueLast, SyncQueueAll:
+	default:
+		return h.fail(fmt.Errorf("hx-sync: unknown strategy %q", strategy))
+	}
+	h.attrs["hx-sync"] = selector + ":" + string(strategy)
+	return h
+}
+
+// DisabledElt는 요청 중에 비활성화할 요소들을 지정합니다.
+func (h *HXAttr) DisabledElt(selectors ...string) *HXAttr {
+	if len(selectors) == 0 {
+		return h.fail(errors.New("hx-disabled-elt: at least one selector is required"))
+	}
+	for _, s := range selectors {
+		if err := checkSelector(s); err != nil {
+			return h.fail(fmt.Errorf("hx-disabled-elt: %w", err))
+		}
+	}
+	h.attrs["hx-disabled-elt"] = strings.Join(selectors, ", ")
+	return h
+}
+
+// Encoding은 hx-encoding 값입니다. htmx는 multipart만 지원합니다.
+type Encoding string
+
+const EncodingMultipart Encoding = "multipart/form-data"
+
+// Encoding은 요청 본문의 인코딩을 지정합니다.
+func (h *HXAttr) Encoding(enc Encoding) *HXAttr {
+	if enc != EncodingMultipart {
+		return h.fail(fmt.Errorf("hx-encoding: unsupported encoding %q", enc))
+	}
+	h.attrs["hx-encoding"] = string(enc)
+	return h
+}
+
+// Progress는 업로드 진행률을 selector 요소에 표시합니다. <progress>면 value와 max를,
+// 그 밖의 요소는 --blazor-progress CSS 변수(0%~100%)를 바꾸고, 요소에서 blazor:progress
+// 이벤트(detail: loaded, total, percent)가 발생합니다. 파일을 보내려면 Encoding(EncodingMultipart)도 지정하고,
+// 스크립트는 Layout.Uploads를 켠 페이지에서만 불러옵니다.
+func (h *HXAttr) Progress(selector string) *HXAttr {
+	return h.selectorAttr("data-blazor-progress", selector)
+}
+
+// Headers는 요청에 추가할 헤더를 hx-headers에 합칩니다.
+func (h *HXAttr) Headers(headers map[string]string) *HXAttr {
+	for k, v := range headers {
+		if k == "" {
+			return h.fail(errors.New("hx-headers: header name is empty"))
+		}
+		h.header(k, v)
+	}
+	return h
+}
+
+func (h *HXAttr) header(key, value string) *HXAttr {
+	if h.headers == nil {
+		h.headers = make(map[string]string)
+	}
+	h.headers[key] = value
+	return h
+}
+
+func (h *HXAttr) selectorAttr(name, selector string) *HXAttr {
+	if err := checkSelector(selector); err != nil {
+		return h.fail(fmt.Errorf("%s: %w", name, err))
+	}
+	h.attrs[name] = selector
+	return h
+}
+
+// clone은 속성과 헤더, 오류를 복사한 HXAttr을 만듭니다.
+func (h *HXAttr) clone() *HXAttr {
+	c := &HXAttr{attrs: make(templ.Attributes, len(h.attrs)), errs: slices.Clone(h.errs)}
+	maps.Copy(c.attrs, h.attrs)
+	if h.headers != nil {
+		c.headers = maps.Clone(h.headers)
+	}
+	return c
+}
+
+func (h *HXAttr) fail(err error) *HXAttr {
+	h.errs = append(h.errs, err)
+	return h
+}
+
+// Err는 빌더에 쌓인 검증 오류를 반환합니다.
+func (h *HXAttr) Err() error {
+	return errors.Join(h.errs...)
+}
+
+// Build는 속성을 완성합니다. 잘못된 값은 속성에 들어가지 않으며 그 오류는 Err로 확인합니다.
+// 템플릿에서는 오류를 응답으로 바꿔 주는 Attrs(ctx)를 씁니다.
+func (h *HXAttr) Build() templ.Attributes {
+	if len(h.headers) != 0 {
+		encoded, _ := json.Marshal(h.headers)
+		h.attrs["hx-headers"] = string(encoded)
+	}
+	return h.attrs
+}
+
+// build는 Build와 같지만 잘못된 값이 있으면 속성 없이 오류를 반환합니다.
+func (h *HXAttr) build() (templ.Attributes, error) {
+	if err := h.Err(); err != nil {
+		return nil, fmt.Errorf("blazor: invalid htmx attributes: %w", err)
+	}
+	return h.Build(), nil
+}
+
+// MustBuild는 Build와 같지만 잘못된 값이 있으면 regexp.MustCompile처럼 panic합니다.
+// 요청에 따라 달라지지 않는 속성에만 씁니다.
+func (h *HXAttr) MustBuild() templ.Attributes {
+	attrs, err := h.build()
+	if err != nil {
+		panic(err)
+	}
+	return attrs
+}
+
+// Attrs는 템플릿에서 펼칠 속성을 만듭니다. 잘못된 값이 있으면 빈 속성을 돌려주고 오류를 ctx에 기록하며,
+// blazor의 렌더러(SetRenderer, Layout, 컴포넌트 핸들러, SSE, WebSocket)는 렌더링이 끝난 뒤 그 오류를 500으로 응답합니다.
+// Vals, Confirm, Call처럼 요청에서 온 값을 쓸 때도 서버가 panic하지 않습니다. blazor 렌더러 밖에서는
+// 오류를 기록할 곳이 없으므로 Err로 직접 확인합니다.
+func (h *HXAttr) Attrs(ctx context.Context) templ.Attributes {
+	attrs, err := h.build()
+	if err != nil {
+		recordRenderError(ctx, err)
+		return templ.Attributes{}
+	}
+	return attrs
+}
+
+func checkSelector(selector string) error {
+	if strings.TrimSpace(selector) == "" {
+		return errors.New("selector is empty")
+	}
+	return nil
+}
+
+// formatDuration은 htmx가 읽을 수 있는 시간 문자열(500ms, 2s)로 바꿉니다.
+func formatDuration(d time.Duration) (string, error) {
+	if d < 0 {
+		return "", fmt.Errorf("negative duration %s", d)
+	}
+	if d%time.Millisecond != 0 {
+		return "", fmt.Errorf("duration %s is finer than a millisecond", d)
+	}
+	if d%time.Second == 0 {
+		return fmt.Sprintf("%ds", d/time.Second), nil
+	}
+	return fmt.Sprintf("%dms", d/time.Millisecond), nil
+}
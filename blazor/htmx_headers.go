@@ -0,0 +1,231 @@
+package blazor
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/gofiber/fiber/v3"
+)
+
+// htmx가 보내는 요청 헤더
+const (
+	HeaderHXRequest        = "HX-Request"
+	HeaderHXBoosted        = "HX-Boosted"
+	HeaderHXHistoryRestore = "HX-History-Restore-Request"
+	HeaderHXCurrentURL     = "HX-Current-URL"
+	HeaderHXPrompt         = "HX-Prompt"
+	HeaderHXTarget         = "HX-Target"
+	HeaderHXTrigger        = "HX-Trigger"
+	HeaderHXTriggerName    = "HX-Trigger-Name"
+)
+
+// htmx가 읽는 응답 헤더
+const (
+	HeaderHXLocation           = "HX-Location"
+	HeaderHXRedirect           = "HX-Redirect"
+	HeaderHXRefresh            = "HX-Refresh"
+	HeaderHXPushURL            = "HX-Push-Url"
+	HeaderHXReplaceURL         = "HX-Replace-Url"
+	HeaderHXRetarget           = "HX-Retarget"
+	HeaderHXReswap             = "HX-Reswap"
+	HeaderHXReselect           = "HX-Reselect"
+	HeaderHXTriggerAfterSwap   = "HX-Trigger-After-Swap"
+	HeaderHXTriggerAfterSettle = "HX-Trigger-After-Settle"
+)
+
+// HXRequest는 htmx가 요청에 실어 보낸 정보입니다.
+type HXRequest struct {
+	Request        bool
+	Boosted        bool
+	HistoryRestore bool
+	CurrentURL     string
+	Prompt         string
+	Target         string
+	Trigger        string
+	TriggerName    string
+}
+
+// HX는 요청 헤더에서 htmx 정보를 읽습니다.
+func HX(c fiber.Ctx) HXRequest {
+	return HXRequest{
+		Request:        c.Get(HeaderHXRequest) == "true",
+		Boosted:        c.Get(HeaderHXBoosted) == "true",
+		HistoryRestore: c.Get(HeaderHXHistoryRestore) == "true",
+		CurrentURL:     c.Get(HeaderHXCurrentURL),
+		Prompt:         c.Get(HeaderHXPrompt),
+		Target:         c.Get(HeaderHXTarget),
+		Trigger:        c.Get(HeaderHXTrigger),
+		TriggerName:    c.Get(HeaderHXTriggerName),
+	}
+}
+
+// IsHTMX는 htmx가 보낸 요청인지 알려줍니다.
+func IsHTMX(c fiber.Ctx) bool {
+	return c.Get(HeaderHXRequest) == "true"
+}
+
+// HXResponse는 htmx 응답 헤더 지시문을 모읍니다. 제로 값을 바로 쓸 수 있습니다.
+type HXResponse struct {
+	headers  map[string]string
+	triggers map[string]map[string]any
+	order    map[string][]string
+	errs     []error
+}
+
+// Location은 전체 페이지를 다시 읽지 않고 url로 이동합니다.
+func (r *HXResponse) Location(url string) *HXResponse {
+	return r.set(HeaderHXLocation, url)
+}
+
+// Redirect는 브라우저를 url로 이동시킵니다.
+func (r *HXResponse) Redirect(url string) *HXResponse {
+	return r.set(HeaderHXRedirect, url)
+}
+
+// Refresh는 페이지 전체를 새로고침합니다.
+func (r *HXResponse) Refresh() *HXResponse {
+	return r.set(HeaderHXRefresh, "true")
+}
+
+// PushURL은 url을 브라우저 히스토리에 넣습니다.
+func (r *HXResponse) PushURL(url string) *HXResponse {
+	return r.set(HeaderHXPushURL, url)
+}
+
+// PreventPushURL은 요소에 지정된 hx-push-url을 무시하게 합니다.
+func (r *HXResponse) PreventPushURL() *HXResponse {
+	return r.set(HeaderHXPushURL, "false")
+}
+
+// ReplaceURL은 현재 히스토리 항목의 URL을 바꿉니다.
+func (r *HXResponse) ReplaceURL(url string) *HXResponse {
+	return r.set(HeaderHXReplaceURL, url)
+}
+
+// Retarget은 응답을 스왑할 대상을 바꿉니다.
+func (r *HXResponse) Retarget(selector string) *HXResponse {
+	if err := checkSelector(selector); err != nil {
+		return r.fail(fmt.Errorf("%s: %w", HeaderHXRetarget, err))
+	}
+	return r.set(HeaderHXRetarget, selector)
+}
+
+// Reswap은 응답의 스왑 방식을 바꿉니다.
+func (r *HXResponse) Reswap(style SwapStyle, modifiers ...SwapModifier) *HXResponse {
+	value, err := buildSwap(style, modifiers)
+	if err != nil {
+		return r.fail(fmt.Errorf("%s: %w", HeaderHXReswap, err))
+	}
+	return r.set(HeaderHXReswap, value)
+}
+
+// Reselect는 응답 중 selector 부분만 스왑하게 합니다.
+func (r *HXResponse) Reselect(selector string) *HXResponse {
+	if err := checkSelector(selector); err != nil {
+		return r.fail(fmt.Errorf("%s: %w", HeaderHXReselect, err))
+	}
+	return r.set(HeaderHXReselect, selector)
+}
+
+// Trigger는 응답을 받자마자 클라이언트에서 event를 발생시킵니다.
+// payload는 JSON으로 인코딩되어 event.detail로 전달되며 nil이면 생략됩니다.
+func (r *HXResponse) Trigger(event string, payload any) *HXResponse {
+	return r.trigger(HeaderHXTrigger, event, payload)
+}
+
+// TriggerAfterSwap은 스왑이 끝난 뒤 event를 발생시킵니다.
+func (r *HXResponse) TriggerAfterSwap(event string, payload any) *HXResponse {
+	return r.trigger(HeaderHXTriggerAfterSwap, event, payload)
+}
+
+// TriggerAfterSettle은 settle 단계가 끝난 뒤 event를 발생시킵니다.
+func (r *HXResponse) TriggerAfterSettle(event string, payload any) *HXResponse {
+	return r.trigger(HeaderHXTriggerAfterSettle, event, payload)
+}
+
+func (r *HXResponse) trigger(header, event string, payload any) *HXResponse {
+	if event == "" || strings.ContainsAny(event, ", ") {
+		return r.fail(fmt.Errorf("%s: invalid event name %q", header, event))
+	}
+	if r.triggers == nil {
+		r.triggers = make(map[string]map[string]any)
+		r.order = make(map[string][]string)
+	}
+	if r.triggers[header] == nil {
+		r.triggers[header] = make(map[string]any)
+	}
+	if _, ok := r.triggers[header][event]; !ok {
+		r.order[header] = append(r.order[header], event)
+	}
+	r.triggers[header][event] = payload
+	return r
+}
+
+func (r *HXResponse) set(header, value string) *HXResponse {
+	if r.headers == nil {
+		r.headers = make(map[string]string)
+	}
+	r.headers[header] = value
+	return r
+}
+
+func (r *HXResponse) fail(err error) *HXResponse {
+	r.errs = append(r.errs, err)
+	return r
+}
+
+// Err는 쌓인 검증 오류를 반환합니다.
+func (r *HXResponse) Err() error {
+	return errors.Join(r.errs...)
+}
+
+// Apply는 모은 지시문을 응답 헤더로 씁니다.
+func (r *HXResponse) Apply(c fiber.Ctx) error {
+	if err := r.Err(); err != nil {
+		return err
+	}
+	for k, v := range r.headers {
+		c.Set(k, v)
+	}
+	for header, events := range r.triggers {
+		value, err := encodeTriggers(r.order[header], events)
+		if err != nil {
+			return fmt.Errorf("%s: %w", header, err)
+		}
+		c.Set(header, value)
+	}
+	return nil
+}
+
+// encodeTriggers는 payload가 없으면 쉼표로 이은 이벤트 이름을, 있으면 JSON 객체를 만듭니다.
+func encodeTriggers(order []string, events map[string]any) (string, error) {
+	plain := true
+	for _, payload := range events {
+		if payload != nil {
+			plain = false
+			break
+		}
+	}
+	if plain {
+		return strings.Join(order, ", "), nil
+	}
+	encoded, err := json.Marshal(events)
+	if err != nil {
+		return "", err
+	}
+	return string(encoded), nil
+}
+
+// Result는 transform이 데이터와 함께 htmx 응답 지시문을 돌려줄 때 씁니다.
+// Data가 nil이면 본문 없이 헤더만 보냅니다(예: Redirect).
+type Result[V any] struct {
+	Data *V
+	HX   HXResponse
+}
+
+// Reply는 data를 담은 Result를 만듭니다.
+func Reply[V any](data *V) *Result[V] {
+	return &Result[V]{Data: data}
+}
@@ -0,0 +1,114 @@
+package blazor
+
+import (
+	"io"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/a-h/templ"
+	"github.com/gofiber/fiber/v3"
+)
+
+func TestHXRequest(t *testing.T) {
+	app := fiber.New()
+	var got HXRequest
+	app.Get("/", func(c fiber.Ctx) error {
+		got = HX(c)
+		return nil
+	})
+
+	req := httptest.NewRequest(fiber.MethodGet, "/", nil)
+	req.Header.Set(HeaderHXRequest, "true")
+	req.Header.Set(HeaderHXTarget, "result")
+	req.Header.Set(HeaderHXTriggerName, "calc_a")
+	req.Header.Set(HeaderHXCurrentURL, "http://localhost/")
+	if _, err := app.Test(req); err != nil {
+		t.Fatal(err)
+	}
+
+	if !got.Request || got.Boosted || got.Target != "result" || got.TriggerName != "calc_a" || got.CurrentURL != "http://localhost/" {
+		t.Errorf("Unexpected HX request: %+v", got)
+	}
+}
+
+func TestHXResponseApply(t *testing.T) {
+	app := fiber.New()
+	app.Get("/", func(c fiber.Ctx) error {
+		var r HXResponse
+		r.PushURL("/items/1").
+			Retarget("#list").
+			Reswap(SwapBeforeEnd, Settle(100*time.Millisecond)).
+			Trigger("saved", nil).
+			Trigger("refresh", nil).
+			TriggerAfterSettle("toast", map[string]string{"message": "Saved"})
+		return r.Apply(c)
+	})
+
+	resp, err := app.Test(httptest.NewRequest(fiber.MethodGet, "/", nil))
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := map[string]string{
+		HeaderHXPushURL:            "/items/1",
+		HeaderHXRetarget:           "#list",
+		HeaderHXReswap:             "beforeend settle:100ms",
+		HeaderHXTrigger:            "saved, refresh",
+		HeaderHXTriggerAfterSettle: `{"toast":{"message":"Saved"}}`,
+	}
+	for k, v := range expected {
+		if got := resp.Header.Get(k); got != v {
+			t.Errorf("Expected %s=%q, got %q", k, v, got)
+		}
+	}
+
+	var r HXResponse
+	if r.Reswap("sideways").Trigger("a b", nil).Err() == nil {
+		t.Errorf("Expected validation errors")
+	}
+}
+
+func TestSetResultRenderer(t *testing.T) {
+	app := fiber.New()
+	app.Post("/", SetResultRenderer(
+		func(data *string) templ.Component {
+			return templ.Raw(*data)
+		},
+		func(req *scopeRequest) (*Result[string], error) {
+			if req.A == 0 {
+				res := Reply[string](nil)
+				res.HX.Redirect("/login")
+				return res, nil
+			}
+			data := "ok"
+			res := Reply(&data)
+			res.HX.Trigger("calculated", map[string]int{"a": req.A})
+			return res, nil
+		},
+	))
+
+	post := func(body string) (string, map[string]string) {
+		req := httptest.NewRequest(fiber.MethodPost, "/", strings.NewReader(body))
+		req.Header.Set(fiber.HeaderContentType, fiber.MIMEApplicationForm)
+		resp, err := app.Test(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		b, _ := io.ReadAll(resp.Body)
+		return string(b), map[string]string{
+			HeaderHXRedirect: resp.Header.Get(HeaderHXRedirect),
+			HeaderHXTrigger:  resp.Header.Get(HeaderHXTrigger),
+		}
+	}
+
+	body, headers := post("a=3")
+	if body != "ok" || headers[HeaderHXTrigger] != `{"calculated":{"a":3}}` {
+		t.Errorf("Unexpected response: %s %v", body, headers)
+	}
+
+	body, headers = post("a=0")
+	if body != "" || headers[HeaderHXRedirect] != "/login" {
+		t.Errorf("Expected header-only redirect, got %q %v", body, headers)
+	}
+}
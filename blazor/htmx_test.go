@@ -0,0 +1,158 @@
+package blazor
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/a-h/templ"
+	"github.com/gofiber/fiber/v3"
+)
+
+func TestHXAttrSwap(t *testing.T) {
+	attrs := Post("/x").Swap(SwapOuterHTML, Settle(200*time.Millisecond), SwapDelay(time.Second), Scroll(ScrollTop, "#list"), Transition()).MustBuild()
+	want := "outerHTML settle:200ms swap:1s scroll:#list:top transition:true"
+	if attrs["hx-swap"] != want {
+		t.Errorf("Expected %q, got %q", want, attrs["hx-swap"])
+	}
+
+	if err := Post("/x").Swap(SwapStyle("sideways")).Err(); err == nil {
+		t.Errorf("Expected error for unknown swap style")
+	}
+	if err := Post("/x").Swap(SwapInnerHTML, Settle(-time.Second)).Err(); err == nil {
+		t.Errorf("Expected error for negative settle")
+	}
+}
+
+func TestHXAttrTrigger(t *testing.T) {
+	attrs := Get("/search").Trigger(
+		On("keyup").Changed().Delay(500*time.Millisecond),
+		On("search").From("closest form"),
+		Every(2*time.Second),
+	).MustBuild()
+	want := "keyup changed delay:500ms, search from:(closest form), every 2s"
+	if attrs["hx-trigger"] != want {
+		t.Errorf("Expected %q, got %q", want, attrs["hx-trigger"])
+	}
+
+	attrs = Get("/x").Trigger(On("click").Filter("ctrlKey").Throttle(time.Second).Queue(QueueLast)).MustBuild()
+	if attrs["hx-trigger"] != "click[ctrlKey] throttle:1s queue:last" {
+		t.Errorf("Unexpected hx-trigger: %q", attrs["hx-trigger"])
+	}
+
+	for _, h := range []*HXAttr{
+		Get("/x").Trigger(),
+		Get("/x").Trigger(On("")),
+		Get("/x").Trigger(On("click, keyup")),
+		Get("/x").Trigger(Every(0)),
+		Get("/x").Trigger(On("click").From("")),
+		Get("/x").Trigger(On("click").Queue("middle")),
+	} {
+		if h.Err() == nil {
+			t.Errorf("Expected validation error, got attrs %v", h.attrs)
+		}
+	}
+}
+
+func TestHXAttrValues(t *testing.T) {
+	attrs := Post("/x").
+		Vals(map[string]int{"page": 2}).
+		Confirm("Sure?").
+		Indicator("#spinner").
+		PushURL(true).
+		Select("#content").
+		Sync("closest form", SyncQueueLast).
+		DisabledElt("this", "#submit").
+		Encoding(EncodingMultipart).
+		Headers(map[string]string{"X-Custom": "1"}).
+		Scope("left").
+		MustBuild()
+
+	expected := map[string]string{
+		"hx-vals":         `{"page":2}`,
+		"hx-confirm":      "Sure?",
+		"hx-indicator":    "#spinner",
+		"hx-push-url":     "true",
+		"hx-select":       "#content",
+		"hx-sync":         "closest form:queue last",
+		"hx-disabled-elt": "this, #submit",
+		"hx-encoding":     "multipart/form-data",
+		"hx-headers":      `{"X-Blazor-Scope":"left","X-Custom":"1"}`,
+	}
+	for k, v := range expected {
+		if attrs[k] != v {
+			t.Errorf("Expected %s=%q, got %q", k, v, attrs[k])
+		}
+	}
+
+	for _, h := range []*HXAttr{
+		Post("/x").Vals([]int{1}),
+		Post("/x").Vals(make(chan int)),
+		Post("/x").Confirm(""),
+		Post("/x").Indicator(" "),
+		Post("/x").Sync("this", "later"),
+		Post("/x").Encoding("text/plain"),
+		Post("/x").DisabledElt(),
+	} {
+		if h.Err() == nil {
+			t.Errorf("Expected validation error, got attrs %v", h.attrs)
+		}
+	}
+}
+
+func TestHXAttrBuildReportsInvalid(t *testing.T) {
+	h := Post("/x").Confirm("")
+	if attrs := h.Build(); attrs["hx-confirm"] != nil || attrs["hx-post"] != "/x" {
+		t.Errorf("Expected Build to leave out the invalid attribute, got %v", attrs)
+	}
+	if h.Err() == nil {
+		t.Errorf("Expected Err to report the invalid attribute")
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Errorf("Expected MustBuild to panic")
+		}
+	}()
+	Post("/x").Confirm("").MustBuild()
+}
+
+func TestHXAttrAttrsFailsRender(t *testing.T) {
+	app := fiber.New()
+	app.Post("/", SetRenderer(
+		func(data *string) templ.Component {
+			return templ.ComponentFunc(func(ctx context.Context, w io.Writer) error {
+				attrs := Post("/x").Confirm(*data).Attrs(ctx)
+				_, err := fmt.Fprintf(w, "<button hx-confirm=%q></button>", attrs["hx-confirm"])
+				return err
+			})
+		},
+		func(req *struct {
+			Message string `form:"message"`
+		}) (*string, error) {
+			return &req.Message, nil
+		},
+	))
+
+	post := func(message string) (int, string) {
+		req := httptest.NewRequest(fiber.MethodPost, "/", strings.NewReader("message="+message))
+		req.Header.Set(fiber.HeaderContentType, fiber.MIMEApplicationForm)
+		resp, err := app.Test(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		body, _ := io.ReadAll(resp.Body)
+		return resp.StatusCode, string(body)
+	}
+
+	if status, body := post("sure"); status != fiber.StatusOK || body != `<button hx-confirm="sure"></button>` {
+		t.Errorf("Expected the button, got %d %s", status, body)
+	}
+	if status, body := post(""); status != fiber.StatusInternalServerError || !strings.Contains(body, "hx-confirm: message is empty") {
+		t.Errorf("Expected 500 for an invalid attribute, got %d %s", status, body)
+	}
+}
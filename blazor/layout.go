@@ -0,0 +1,177 @@
+package blazor
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/a-h/templ"
+	"github.com/gofiber/fiber/v3"
+)
+
+const defaultStaticPrefix = "/statics"
+
+// Meta는 <head>에 들어갈 meta 태그 하나입니다. Open Graph처럼 property를 쓰는 태그는 Property를 채웁니다.
+type Meta struct {
+	Name     string
+	Property string
+	Content  string
+}
+
+// Attrs는 meta 태그 속성을 만듭니다.
+func (m Meta) Attrs() templ.Attributes {
+	attrs := templ.Attributes{"content": m.Content}
+	if m.Name != "" {
+		attrs["name"] = m.Name
+	}
+	if m.Property != "" {
+		attrs["property"] = m.Property
+	}
+	return attrs
+}
+
+// Layout은 Page가 그리는 문서 틀입니다. 제로 값도 기본 제목과 언어로 렌더링됩니다.
+type Layout struct {
+	Title       string
+	Description string
+	Lang        string
+
+	// StaticPrefix는 Static을 마운트한 경로입니다. 기본값은 "/statics"입니다.
+	StaticPrefix string
+	// Favicon, Stylesheets, Scripts는 주어진 URL을 그대로 씁니다.
+	Favicon     string
+	Stylesheets []string
+	Scripts     []string
+	Meta        []Meta
+	// HTMXConfig는 htmx-config meta 태그로 들어갑니다. 예: {"defaultSwapStyle": "outerHTML"}
+	HTMXConfig map[string]any
+	BodyAttrs  templ.Attributes
+
+	// SSE, WS, Uploads는 htmx SSE 확장(SSEConnect), WebSocket 확장(WSConnect), 업로드 진행률 스크립트
+	// (HXAttr.Progress)를 불러옵니다. 쓰는 페이지에서만 켜서 요청을 줄입니다.
+	SSE     bool
+	WS      bool
+	Uploads bool
+
+	// Template이 있으면 기본 문서 대신 씁니다. Head(layout)로 프레임워크 스크립트를 넣을 수 있습니다.
+	// Security를 쓴다면 <body { blazor.WithCSRFHeader(ctx, layout.BodyAttrs)... }>처럼 CSRF 헤더를 붙여야
+	// htmx 요청이 403으로 거부되지 않습니다.
+	Template func(layout Layout, content templ.Component) templ.Component
+}
+
+// Asset은 Static으로 제공되는 파일의 URL을 만듭니다.
+func (l Layout) Asset(name string) string {
+	prefix := strings.TrimSuffix(l.StaticPrefix, "/")
+	if l.StaticPrefix == "" {
+		prefix = defaultStaticPrefix
+	}
+	return prefix + "/" + strings.TrimPrefix(name, "/")
+}
+
+// Err는 레이아웃 설정의 문제를 반환합니다.
+func (l Layout) Err() error {
+	if _, err := json.Marshal(l.HTMXConfig); err != nil {
+		return fmt.Errorf("blazor: invalid htmx config: %w", err)
+	}
+	return nil
+}
+
+// Component는 content를 이 레이아웃으로 감싼 컴포넌트를 반환합니다.
+func (l Layout) Component(content templ.Component) templ.Component {
+	l = l.withDefaults()
+	if l.Template != nil {
+		return l.Template(l, content)
+	}
+	return Document(l, content)
+}
+
+// Render는 요청에 지정된 제목과 설명을 반영해 content를 전체 문서로 응답합니다.
+func (l Layout) Render(c fiber.Ctx, content templ.Component) error {
+	c.Set(fiber.HeaderContentType, fiber.MIMETextHTMLCharsetUTF8)
+	return renderChecked(c.Context(), l.forRoute(c).Component(content), c.Res().Response().BodyWriter())
+}
+
+func (l Layout) forRoute(c fiber.Ctx) Layout {
+	if title, ok := c.Locals(localTitle).(string); ok {
+		l.Title = title
+	}
+	if description, ok := c.Locals(localDescription).(string); ok {
+		l.Description = description
+	}
+	return l
+}
+
+// UseLayout은 이후 핸들러가 쓸 레이아웃을 지정하는 미들웨어입니다.
+// SetRenderer는 htmx가 아닌 요청이나 hx-boost 요청에 이 레이아웃으로 전체 문서를 응답합니다.
+func UseLayout(layout Layout) fiber.Handler {
+	if err := layout.Err(); err != nil {
+		panic(err)
+	}
+	return func(c fiber.Ctx) error {
+		c.Locals(localLayout, layout)
+		return c.Next()
+	}
+}
+
+// LayoutOf는 UseLayout으로 지정된 레이아웃을 반환합니다.
+func LayoutOf(c fiber.Ctx) (Layout, bool) {
+	layout, ok := c.Locals(localLayout).(Layout)
+	return layout, ok
+}
+
+// WantsFullPage는 응답을 전체 문서로 감싸야 하는 요청인지 알려줍니다.
+// 주소창으로 직접 열었거나 JavaScript 없이 폼을 제출한 요청, hx-boost 요청, 히스토리 복원 요청이 해당합니다.
+func WantsFullPage(c fiber.Ctx) bool {
+	hx := HX(c)
+	return !hx.Request || hx.Boosted || hx.HistoryRestore
+}
+
+func (l Layout) withDefaults() Layout {
+	if l.Title == "" {
+		l.Title = defaultTitle
+	}
+	if l.Lang == "" {
+		l.Lang = defaultLang
+	}
+	return l
+}
+
+// htmxConfig는 CSP nonce가 있으면 htmx가 넣는 inline script와 style에도 같은 nonce를 쓰게 합니다.
+func (l Layout) htmxConfig(ctx context.Context) string {
+	config := l.HTMXConfig
+	if nonce := templ.GetNonce(ctx); nonce != "" {
+		config = make(map[string]any, len(l.HTMXConfig)+2)
+		for k, v := range l.HTMXConfig {
+			config[k] = v
+		}
+		config["inlineScriptNonce"] = nonce
+		config["inlineStyleNonce"] = nonce
+	}
+	if len(config) == 0 {
+		return ""
+	}
+	encoded, err := json.Marshal(config)
+	if err != nil {
+		return ""
+	}
+	return string(encoded)
+}
+
+type localKey int
+
+const (
+	localTitle localKey = iota
+	localDescription
+	localLayout
+)
+
+// SetTitle은 이번 요청에서 렌더링할 문서 제목을 바꿉니다.
+func SetTitle(c fiber.Ctx, title string) {
+	c.Locals(localTitle, title)
+}
+
+// SetDescription은 이번 요청에서 렌더링할 description meta 태그를 바꿉니다.
+func SetDescription(c fiber.Ctx, description string) {
+	c.Locals(localDescription, description)
+}
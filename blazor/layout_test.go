@@ -0,0 +1,182 @@
+package blazor
+
+import (
+	"context"
+	"io"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/a-h/templ"
+	"github.com/gofiber/fiber/v3"
+)
+
+func TestLayoutHead(t *testing.T) {
+	layout := Layout{
+		StaticPrefix: "/assets/",
+		Favicon:      "/favicon.ico",
+		Stylesheets:  []string{"/app.css"},
+		Scripts:      []string{"/app.js"},
+		Meta:         []Meta{{Property: "og:title", Content: "Calc"}},
+		HTMXConfig:   map[string]any{"defaultSwapStyle": "outerHTML"},
+		BodyAttrs:    templ.Attributes{"class": "dark"},
+	}
+
+	var sb strings.Builder
+	if err := layout.Component(templ.Raw("<main></main>")).Render(context.Background(), &sb); err != nil {
+		t.Fatal(err)
+	}
+	html := sb.String()
+
+	for _, want := range []string{
+		`<html lang="en">`,
+		`<title>Fiber Blazor App</title>`,
+		`<meta content="Calc" property="og:title">`,
+		`<meta name="htmx-config" content="{&#34;defaultSwapStyle&#34;:&#34;outerHTML&#34;}">`,
+		`<link rel="icon" href="/favicon.ico">`,
+		`<link rel="stylesheet" href="/app.css">`,
+		`<script src="/assets/htmx.min.js"></script>`,
+		`<script src="/assets/tailwindcss.js"></script>`,
+		`<script src="/app.js"></script>`,
+		`<body class="dark"><main></main></body>`,
+	} {
+		if !strings.Contains(html, want) {
+			t.Errorf("Expected %q in %s", want, html)
+		}
+	}
+	if strings.Contains(html, `name="description"`) {
+		t.Errorf("Unexpected description meta in %s", html)
+	}
+	if strings.Contains(html, "blazor-dev.js") {
+		t.Errorf("Unexpected dev reload script outside dev mode in %s", html)
+	}
+	for _, script := range []string{"htmx-ext-sse.js", "htmx-ext-ws.js", "blazor-upload.js"} {
+		if strings.Contains(html, script) {
+			t.Errorf("Expected %s only when the layout asks for it, got %s", script, html)
+		}
+	}
+
+	sb.Reset()
+	layout = Layout{SSE: true, WS: true, Uploads: true}
+	if err := layout.Component(templ.Raw("")).Render(context.Background(), &sb); err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{
+		`<script src="/statics/htmx-ext-sse.js"></script>`,
+		`<script src="/statics/htmx-ext-ws.js"></script>`,
+		`<script src="/statics/blazor-upload.js"></script>`,
+	} {
+		if !strings.Contains(sb.String(), want) {
+			t.Errorf("Expected %q in %s", want, sb.String())
+		}
+	}
+}
+
+func TestLayoutDevReload(t *testing.T) {
+	t.Setenv(EnvDev, "1")
+
+	var sb strings.Builder
+	if err := (Layout{}).Component(templ.Raw("")).Render(context.Background(), &sb); err != nil {
+		t.Fatal(err)
+	}
+	if want := `<script src="/statics/blazor-dev.js" data-url="` + DevReloadPath + `"></script>`; !strings.Contains(sb.String(), want) {
+		t.Errorf("Expected %q in %s", want, sb.String())
+	}
+}
+
+func TestLayoutTemplate(t *testing.T) {
+	layout := Layout{
+		Title: "Custom",
+		Template: func(l Layout, content templ.Component) templ.Component {
+			return templ.ComponentFunc(func(ctx context.Context, w io.Writer) error {
+				io.WriteString(w, "<article>"+l.Title+"</article>")
+				return content.Render(ctx, w)
+			})
+		},
+	}
+
+	var sb strings.Builder
+	if err := layout.Component(templ.Raw("<p>body</p>")).Render(context.Background(), &sb); err != nil {
+		t.Fatal(err)
+	}
+	if got := sb.String(); got != "<article>Custom</article><p>body</p>" {
+		t.Errorf("Unexpected custom layout: %s", got)
+	}
+}
+
+func TestInitLayoutRouteOverrides(t *testing.T) {
+	app := fiber.New()
+	handler := InitLayout(templ.Raw("<main></main>"), Layout{Title: "Home", Description: "Default"})
+	app.Get("/", handler)
+	app.Get("/about", func(c fiber.Ctx) error {
+		SetTitle(c, "About")
+		SetDescription(c, "About us")
+		return c.Next()
+	}, handler)
+
+	for path, want := range map[string][]string{
+		"/":      {`<title>Home</title>`, `content="Default"`},
+		"/about": {`<title>About</title>`, `content="About us"`},
+	} {
+		resp, err := app.Test(httptest.NewRequest(fiber.MethodGet, path, nil))
+		if err != nil {
+			t.Fatal(err)
+		}
+		body, _ := io.ReadAll(resp.Body)
+		for _, w := range want {
+			if !strings.Contains(string(body), w) {
+				t.Errorf("%s: expected %q in %s", path, w, body)
+			}
+		}
+	}
+}
+
+func TestLayoutErr(t *testing.T) {
+	if err := (Layout{HTMXConfig: map[string]any{"bad": func() {}}}).Err(); err == nil {
+		t.Error("Expected an error for an unencodable htmx config")
+	}
+}
+
+func TestSetRendererFullPage(t *testing.T) {
+	app := fiber.New()
+	app.Use(UseLayout(Layout{Title: "Calc"}))
+	app.Post("/", SetRenderer(
+		func(data *scopeRequest) templ.Component {
+			return templ.Raw("<p>result</p>")
+		},
+		func(req *scopeRequest) (*scopeRequest, error) {
+			return req, nil
+		},
+	))
+
+	for _, tc := range []struct {
+		name    string
+		headers map[string]string
+		full    bool
+	}{
+		{"plain", nil, true},
+		{"htmx", map[string]string{HeaderHXRequest: "true"}, false},
+		{"boosted", map[string]string{HeaderHXRequest: "true", HeaderHXBoosted: "true"}, true},
+	} {
+		req := httptest.NewRequest(fiber.MethodPost, "/", strings.NewReader("a=1"))
+		req.Header.Set(fiber.HeaderContentType, fiber.MIMEApplicationForm)
+		for k, v := range tc.headers {
+			req.Header.Set(k, v)
+		}
+		resp, err := app.Test(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		body, _ := io.ReadAll(resp.Body)
+
+		if full := strings.Contains(string(body), "<title>Calc</title>"); full != tc.full {
+			t.Errorf("%s: expected full page %v, got %s", tc.name, tc.full, body)
+		}
+		if !strings.Contains(string(body), "<p>result</p>") {
+			t.Errorf("%s: expected the fragment in %s", tc.name, body)
+		}
+		if vary := resp.Header.Get(fiber.HeaderVary); vary != HeaderHXRequest {
+			t.Errorf("%s: unexpected Vary header %q", tc.name, vary)
+		}
+	}
+}
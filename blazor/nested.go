@@ -0,0 +1,200 @@
+package blazor
+
+import (
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/gofiber/fiber/v3"
+)
+
+// List는 구조체 슬라이스 필드의 바인더입니다. At(i)로 i번째 행의 바인더를 얻습니다.
+type List[B any] struct {
+	binding *Binding
+	name    string
+	build   func(*Binding) B
+}
+
+// NewList는 flazor가 만든 코드에서 쓰는 List 생성자입니다.
+func NewList[B any](b *Binding, name string, build func(*Binding) B) List[B] {
+	return List[B]{binding: b, name: name, build: build}
+}
+
+// At은 i번째 행의 바인더를 반환합니다. 필드 Name은 name.i.field 꼴입니다.
+func (l List[B]) At(i int) B {
+	return l.build(l.binding.Nested(l.name + "." + strconv.Itoa(i)))
+}
+
+// Field는 슬라이스 전체에 대한 필드입니다. 길이 검증 오류나 행을 담을 요소의 ID에 씁니다.
+func (l List[B]) Field() Field {
+	return l.binding.Field(l.name)
+}
+
+// Map은 map[string]T 필드의 바인더입니다. 키에는 점(.)을 쓸 수 없습니다.
+type Map struct {
+	binding *Binding
+	name    string
+}
+
+// NewMap은 flazor가 만든 코드에서 쓰는 Map 생성자입니다.
+func NewMap(b *Binding, name string) Map {
+	return Map{binding: b, name: name}
+}
+
+// Key는 key 항목의 필드를 반환합니다. 필드 Name은 name.key 꼴입니다.
+func (m Map) Key(key string) Field {
+	return m.binding.Field(m.name + "." + key)
+}
+
+// Field는 맵 전체에 대한 필드입니다.
+func (m Map) Field() Field {
+	return m.binding.Field(m.name)
+}
+
+// requestValues는 쿼리와 폼 본문 값을 점 표기 키로 모읍니다. a[b][0]은 a.b.0이 됩니다.
+func requestValues(c fiber.Ctx) map[string][]string {
+	values := make(map[string][]string)
+	add := func(k, v []byte) {
+		key := bracketPath(string(k))
+		values[key] = append(values[key], string(v))
+	}
+	for k, v := range c.Request().URI().QueryArgs().All() {
+		add(k, v)
+	}
+	for k, v := range c.Request().PostArgs().All() {
+		add(k, v)
+	}
+	if form, err := c.MultipartForm(); err == nil {
+		for k, vs := range form.Value {
+			for _, v := range vs {
+				add([]byte(k), []byte(v))
+			}
+		}
+	}
+	return values
+}
+
+func bracketPath(key string) string {
+	if !strings.Contains(key, "[") {
+		return key
+	}
+	key = strings.ReplaceAll(key, "][", ".")
+	key = strings.ReplaceAll(key, "[", ".")
+	key = strings.ReplaceAll(key, "]", "")
+	return strings.TrimSuffix(key, ".")
+}
+
+// bindMaps는 Fiber 바인더가 채우지 못하는 map[string]T 필드를 name.key 값으로 채웁니다.
+// 변환할 수 없는 값은 검증 오류로 돌려줍니다.
+func bindMaps(out any, values map[string][]string) ValidationErrors {
+	rv := reflect.ValueOf(out)
+	for rv.Kind() == reflect.Pointer {
+		if rv.IsNil() {
+			return nil
+		}
+		rv = rv.Elem()
+	}
+	if rv.Kind() != reflect.Struct {
+		return nil
+	}
+
+	errs := ValidationErrors{}
+	bindStructMaps(rv, "", values, errs)
+	if len(errs) == 0 {
+		return nil
+	}
+	return errs
+}
+
+func bindStructMaps(rv reflect.Value, prefix string, values map[string][]string, errs ValidationErrors) {
+	rt := rv.Type()
+	for i := range rt.NumField() {
+		sf := rt.Field(i)
+		if !sf.IsExported() {
+			continue
+		}
+		if promoted(sf) {
+			if embedded, ok := structOf(rv.Field(i)); ok {
+				bindStructMaps(embedded, prefix, values, errs)
+			}
+			continue
+		}
+		key := prefix + FieldKey(sf)
+		field := rv.Field(i)
+
+		switch {
+		case isFileType(sf.Type):
+			// 업로드 파일은 Fiber 바인더가 채웁니다.
+		case field.Kind() == reflect.Map && field.Type().Key().Kind() == reflect.String:
+			bindMap(field, key, values, errs)
+		case field.Kind() == reflect.Struct:
+			bindStructMaps(field, key+".", values, errs)
+		case field.Kind() == reflect.Pointer && field.Type().Elem().Kind() == reflect.Struct && !field.IsNil():
+			bindStructMaps(field.Elem(), key+".", values, errs)
+		case field.Kind() == reflect.Slice:
+			for j := range field.Len() {
+				elem := field.Index(j)
+				if elem.Kind() == reflect.Pointer && !elem.IsNil() {
+					elem = elem.Elem()
+				}
+				if elem.Kind() == reflect.Struct {
+					bindStructMaps(elem, key+"."+strconv.Itoa(j)+".", values, errs)
+				}
+			}
+		}
+	}
+}
+
+func bindMap(field reflect.Value, name string, values map[string][]string, errs ValidationErrors) {
+	prefix := name + "."
+	elemType := field.Type().Elem()
+	for k, vs := range values {
+		entry, ok := strings.CutPrefix(k, prefix)
+		if !ok || entry == "" || strings.Contains(entry, ".") || len(vs) == 0 {
+			continue
+		}
+		elem := reflect.New(elemType).Elem()
+		if !setScalar(elem, vs[len(vs)-1]) {
+			errs.Add(k, "has an invalid value")
+			continue
+		}
+		if field.IsNil() {
+			field.Set(reflect.MakeMap(field.Type()))
+		}
+		field.SetMapIndex(reflect.ValueOf(entry).Convert(field.Type().Key()), elem)
+	}
+}
+
+func setScalar(v reflect.Value, s string) bool {
+	switch v.Kind() {
+	case reflect.String:
+		v.SetString(s)
+	case reflect.Bool:
+		b, err := strconv.ParseBool(s)
+		if err != nil {
+			return false
+		}
+		v.SetBool(b)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		n, err := strconv.ParseInt(s, 10, v.Type().Bits())
+		if err != nil {
+			return false
+		}
+		v.SetInt(n)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		n, err := strconv.ParseUint(s, 10, v.Type().Bits())
+		if err != nil {
+			return false
+		}
+		v.SetUint(n)
+	case reflect.Float32, reflect.Float64:
+		f, err := strconv.ParseFloat(s, v.Type().Bits())
+		if err != nil {
+			return false
+		}
+		v.SetFloat(f)
+	default:
+		return false
+	}
+	return true
+}
@@ -0,0 +1,161 @@
+package blazor
+
+import (
+	"fmt"
+	"io"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/a-h/templ"
+	"github.com/gofiber/fiber/v3"
+)
+
+type addressRequest struct {
+	Street string `form:"street_y" validate:"required"`
+}
+
+type rowRequest struct {
+	Name string `form:"name_z" validate:"required"`
+	Qty  int    `form:"qty_z" validate:"min=1"`
+}
+
+type orderRequest struct {
+	Title   string          `form:"title_x"`
+	Address addressRequest  `form:"address_x"`
+	Rows    []rowRequest    `form:"rows_x"`
+	Attrs   map[string]int  `form:"attrs_x"`
+	Billing *addressRequest `form:"billing_x"`
+}
+
+type rowBinding struct {
+	*Binding
+	Name Field
+}
+
+func TestNestedBinding(t *testing.T) {
+	b := NewBinding("s1")
+	address := b.Nested("address_x")
+	if f := address.Field("street_y"); f.Name != "s1:address_x.street_y" || f.ID != "s1-address_x-street_y" {
+		t.Errorf("Unexpected nested field %+v", f)
+	}
+
+	rows := NewList(b, "rows_x", func(b *Binding) rowBinding {
+		return rowBinding{Binding: b, Name: b.Field("name_z")}
+	})
+	if f := rows.At(2).Name; f.Name != "s1:rows_x.2.name_z" || f.ID != "s1-rows_x-2-name_z" {
+		t.Errorf("Unexpected list field %+v", f)
+	}
+	if f := rows.Field(); f.Name != "s1:rows_x" {
+		t.Errorf("Unexpected list field %+v", f)
+	}
+
+	attrs := NewMap(b, "attrs_x")
+	if f := attrs.Key("color"); f.Name != "s1:attrs_x.color" || f.ID != "s1-attrs_x-color" {
+		t.Errorf("Unexpected map field %+v", f)
+	}
+}
+
+func TestValidateNested(t *testing.T) {
+	errs := Validate(&orderRequest{
+		Rows:    []rowRequest{{Name: "a", Qty: 1}, {Qty: 0}},
+		Billing: &addressRequest{},
+	})
+	expected := []string{"address_x.street_y", "rows_x.1.name_z", "rows_x.1.qty_z", "billing_x.street_y"}
+	for _, key := range expected {
+		if len(errs.Get(key)) != 1 {
+			t.Errorf("Expected an error on %s, got %v", key, errs)
+		}
+	}
+	if len(errs) != len(expected) {
+		t.Errorf("Expected %d errors, got %v", len(expected), errs)
+	}
+}
+
+func TestSetRendererNested(t *testing.T) {
+	app := fiber.New()
+	app.Post("/", SetRenderer(
+		func(data *string) templ.Component {
+			return templ.Raw(*data)
+		},
+		func(req *orderRequest) (*string, error) {
+			out := fmt.Sprintf("%s|%s|%d|%d", req.Title, req.Address.Street, len(req.Rows), req.Attrs["size"])
+			for _, row := range req.Rows {
+				out += fmt.Sprintf("|%s:%d", row.Name, row.Qty)
+			}
+			return &out, nil
+		},
+	))
+
+	post := func(body string) (int, string) {
+		req := httptest.NewRequest(fiber.MethodPost, "/", strings.NewReader(body))
+		req.Header.Set(fiber.HeaderContentType, fiber.MIMEApplicationForm)
+		resp, err := app.Test(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		b, _ := io.ReadAll(resp.Body)
+		return resp.StatusCode, string(b)
+	}
+
+	status, body := post("title_x=t&address_x.street_y=main&rows_x.0.name_z=a&rows_x.0.qty_z=2&rows_x[1][name_z]=b&rows_x[1][qty_z]=3&attrs_x.size=7")
+	if status != fiber.StatusOK || body != "t|main|2|7|a:2|b:3" {
+		t.Errorf("Unexpected nested bind: %d %s", status, body)
+	}
+
+	if status, _ := post("address_x.street_y=main&rows_x.0.name_z=a&rows_x.0.qty_z=0"); status != fiber.StatusUnprocessableEntity {
+		t.Errorf("Expected 422 for an invalid row, got %d", status)
+	}
+	if status, _ := post("address_x.street_y=main&attrs_x.size=big"); status != fiber.StatusUnprocessableEntity {
+		t.Errorf("Expected 422 for an invalid map value, got %d", status)
+	}
+}
+
+// 생성된 Binded 타입처럼 임베드 타입은 공개되어 있어야 바인딩됩니다.
+// 임베드 포인터는 Fiber 바인더가 할당하지 않으므로 값으로 임베드합니다.
+type EmbeddedStreet struct {
+	Street string `form:"street_w" validate:"required"`
+}
+
+type EmbeddedNote struct {
+	Note string `form:"note_w" validate:"maxlen=3"`
+}
+
+type contactRequest struct {
+	EmbeddedStreet
+	EmbeddedNote
+	Phone string `form:"phone_x"`
+}
+
+func TestEmbeddedFieldsArePromoted(t *testing.T) {
+	app := fiber.New()
+	app.Post("/", SetRenderer(
+		func(data *string) templ.Component {
+			return templ.Raw(*data)
+		},
+		func(req *contactRequest) (*string, error) {
+			out := req.Street + "|" + req.Phone + "|" + req.Note
+			return &out, nil
+		},
+	))
+
+	post := func(body string) (int, string) {
+		req := httptest.NewRequest(fiber.MethodPost, "/", strings.NewReader(body))
+		req.Header.Set(fiber.HeaderContentType, fiber.MIMEApplicationForm)
+		resp, err := app.Test(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		b, _ := io.ReadAll(resp.Body)
+		return resp.StatusCode, string(b)
+	}
+
+	if status, body := post("street_w=main&phone_x=010&note_w=hi"); status != fiber.StatusOK || body != "main|010|hi" {
+		t.Errorf("Unexpected embedded bind: %d %s", status, body)
+	}
+
+	errs := Validate(&contactRequest{EmbeddedNote: EmbeddedNote{Note: "long"}})
+	if len(errs.Get("street_w")) != 1 || len(errs.Get("note_w")) != 1 {
+		t.Errorf("Expected promoted error keys, got %v", errs)
+	}
+}
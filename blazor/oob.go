@@ -0,0 +1,67 @@
+package blazor
+
+import (
+	"context"
+	"fmt"
+	"io"
+
+	"github.com/a-h/templ"
+)
+
+// Fragments는 주 컴포넌트 하나와 여러 out-of-band 컴포넌트를 한 응답으로 렌더링합니다.
+// templ.Component를 구현하므로 SetRenderer의 componentFunc에서 그대로 반환하면 됩니다.
+type Fragments struct {
+	primary templ.Component
+	parts   []oobPart
+}
+
+type oobPart struct {
+	field   Field
+	style   SwapStyle
+	content templ.Component
+}
+
+// Compose는 primary를 주 응답으로 하는 Fragments를 만듭니다. primary는 nil이어도 됩니다.
+func Compose(primary templ.Component) *Fragments {
+	return &Fragments{primary: primary}
+}
+
+// OOB는 content를 field 요소의 안쪽(innerHTML)으로 스왑합니다.
+func (f *Fragments) OOB(field Field, content templ.Component) *Fragments {
+	return f.OOBSwap(field, SwapInnerHTML, content)
+}
+
+// OOBSwap은 지정한 스왑 방식으로 content를 field 요소에 스왑합니다.
+// outerHTML이면 대상 요소가 같은 ID를 가진 <div>로 바뀝니다.
+func (f *Fragments) OOBSwap(field Field, style SwapStyle, content templ.Component) *Fragments {
+	f.parts = append(f.parts, oobPart{field: field, style: style, content: content})
+	return f
+}
+
+func (f *Fragments) Render(ctx context.Context, w io.Writer) error {
+	if f.primary != nil {
+		if err := f.primary.Render(ctx, w); err != nil {
+			return err
+		}
+	}
+
+	for _, part := range f.parts {
+		if !part.style.valid() {
+			return fmt.Errorf("blazor: unknown oob swap style %q", part.style)
+		}
+		// scope 없이 만든 필드는 요청한 인스턴스의 ID로 맞춰야 대상 요소를 찾을 수 있습니다.
+		field := part.field.resolve(ctx)
+		if _, err := fmt.Fprintf(w, `<div id="%s" hx-swap-oob="%s">`, templ.EscapeString(field.ID), part.style); err != nil {
+			return err
+		}
+		if part.content != nil {
+			if err := part.content.Render(ctx, w); err != nil {
+				return err
+			}
+		}
+		if _, err := io.WriteString(w, "</div>"); err != nil {
+			return err
+		}
+	}
+	return nil
+}
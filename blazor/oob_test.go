@@ -0,0 +1,43 @@
+package blazor
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/a-h/templ"
+)
+
+func TestFragmentsRender(t *testing.T) {
+	b := NewBinding("")
+	f := Compose(templ.Raw("<p>sum</p>")).
+		OOB(b.ID("badge"), templ.Raw("3")).
+		OOBSwap(b.ID("toast"), SwapBeforeEnd, templ.Raw("<li>saved</li>"))
+
+	var sb strings.Builder
+	if err := f.Render(WithScope(context.Background(), "left"), &sb); err != nil {
+		t.Fatal(err)
+	}
+	want := `<p>sum</p>` +
+		`<div id="left-badge" hx-swap-oob="innerHTML">3</div>` +
+		`<div id="left-toast" hx-swap-oob="beforeend"><li>saved</li></div>`
+	if sb.String() != want {
+		t.Errorf("Expected %s, got %s", want, sb.String())
+	}
+}
+
+func TestFragmentsKeepExplicitScope(t *testing.T) {
+	f := Compose(nil).OOB(NewBinding("right").ID("badge"), templ.Raw("1"))
+
+	var sb strings.Builder
+	if err := f.Render(WithScope(context.Background(), "left"), &sb); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(sb.String(), `id="right-badge"`) {
+		t.Errorf("Expected explicitly scoped ID to be kept, got %s", sb.String())
+	}
+
+	if err := Compose(nil).OOBSwap(Field{ID: "x"}, "sideways", templ.Raw("")).Render(context.Background(), &sb); err == nil {
+		t.Errorf("Expected error for unknown swap style")
+	}
+}
@@ -0,0 +1,143 @@
+package blazor
+
+import (
+	"fmt"
+	"net/url"
+	"regexp"
+	"strings"
+
+	"github.com/a-h/templ"
+	"github.com/gofiber/fiber/v3"
+)
+
+// Endpoint는 Router로 등록한 라우트입니다. 템플릿에서 URL 문자열 대신 씁니다.
+// 제로 값은 등록되지 않은 엔드포인트이며 HX().Err()가 오류를 반환합니다.
+type Endpoint struct {
+	method string
+	path   string
+}
+
+// Method는 HTTP 메서드입니다.
+func (e Endpoint) Method() string {
+	return e.method
+}
+
+// Path는 경로 패턴입니다(예: /items/:id).
+func (e Endpoint) Path() string {
+	return e.path
+}
+
+// Registered는 Router로 등록된 엔드포인트인지 알려줍니다.
+func (e Endpoint) Registered() bool {
+	return e.method != ""
+}
+
+// pathParam은 Fiber 경로의 매개변수(:name, :name?, :name<int>, *, +)를 찾습니다.
+var pathParam = regexp.MustCompile(`:[A-Za-z0-9_]+(<[^>]*>)?\??|\*|\+`)
+
+// URL은 경로 매개변수를 순서대로 채운 URL을 만듭니다.
+// 선택 매개변수(:name?)에 빈 값을 주면 그 경로 조각을 뺍니다.
+func (e Endpoint) URL(params ...any) (string, error) {
+	if !e.Registered() {
+		return "", fmt.Errorf("blazor: endpoint is not registered")
+	}
+
+	matches := pathParam.FindAllStringIndex(e.path, -1)
+	if len(matches) != len(params) {
+		return "", fmt.Errorf("blazor: %s %s takes %d path parameters, got %d", e.method, e.path, len(matches), len(params))
+	}
+
+	var sb strings.Builder
+	last := 0
+	for i, m := range matches {
+		value := fmt.Sprint(params[i])
+		segment := e.path[m[0]:m[1]]
+		prefix := e.path[last:m[0]]
+		if value == "" {
+			if !strings.HasSuffix(segment, "?") {
+				return "", fmt.Errorf("blazor: %s %s: parameter %s must not be empty", e.method, e.path, segment)
+			}
+			prefix = strings.TrimSuffix(prefix, "/")
+		}
+		sb.WriteString(prefix)
+		if segment == "*" || segment == "+" {
+			// 와일드카드는 여러 경로 조각을 담을 수 있으므로 /는 그대로 둡니다.
+			parts := strings.Split(value, "/")
+			for j, part := range parts {
+				parts[j] = url.PathEscape(part)
+			}
+			sb.WriteString(strings.Join(parts, "/"))
+		} else {
+			sb.WriteString(url.PathEscape(value))
+		}
+		last = m[1]
+	}
+	sb.WriteString(e.path[last:])
+	return sb.String(), nil
+}
+
+// HX는 이 엔드포인트로 요청을 보내는 HXAttr을 만듭니다.
+func (e Endpoint) HX(params ...any) *HXAttr {
+	url, err := e.URL(params...)
+	if err != nil {
+		return &HXAttr{attrs: templ.Attributes{}, errs: []error{err}}
+	}
+	return &HXAttr{attrs: templ.Attributes{"hx-" + strings.ToLower(e.method): url}}
+}
+
+// Router는 fiber.Router에 라우트를 등록하고 그 Endpoint를 돌려줍니다.
+type Router struct {
+	router    fiber.Router
+	prefix    string
+	endpoints *[]Endpoint
+}
+
+// NewRouter는 router 위에 Router를 만듭니다.
+func NewRouter(router fiber.Router) *Router {
+	return &Router{router: router, endpoints: &[]Endpoint{}}
+}
+
+// Group은 prefix 아래에 라우트를 등록하는 Router를 만듭니다.
+func (r *Router) Group(prefix string, handlers ...any) *Router {
+	return &Router{
+		router:    r.router.Group(prefix, handlers...),
+		prefix:    r.prefix + strings.TrimSuffix(prefix, "/"),
+		endpoints: r.endpoints,
+	}
+}
+
+func (r *Router) Get(path string, handler any, handlers ...any) Endpoint {
+	r.router.Get(path, handler, handlers...)
+	return r.add(fiber.MethodGet, path)
+}
+
+func (r *Router) Post(path string, handler any, handlers ...any) Endpoint {
+	r.router.Post(path, handler, handlers...)
+	return r.add(fiber.MethodPost, path)
+}
+
+func (r *Router) Put(path string, handler any, handlers ...any) Endpoint {
+	r.router.Put(path, handler, handlers...)
+	return r.add(fiber.MethodPut, path)
+}
+
+func (r *Router) Patch(path string, handler any, handlers ...any) Endpoint {
+	r.router.Patch(path, handler, handlers...)
+	return r.add(fiber.MethodPatch, path)
+}
+
+func (r *Router) Delete(path string, handler any, handlers ...any) Endpoint {
+	r.router.Delete(path, handler, handlers...)
+	return r.add(fiber.MethodDelete, path)
+}
+
+// Endpoints는 이 Router와 그 그룹으로 등록한 엔드포인트 목록입니다.
+func (r *Router) Endpoints() []Endpoint {
+	return append([]Endpoint(nil), *r.endpoints...)
+}
+
+func (r *Router) add(method, path string) Endpoint {
+	e := Endpoint{method: method, path: r.prefix + path}
+	*r.endpoints = append(*r.endpoints, e)
+	return e
+}
@@ -0,0 +1,48 @@
+package blazor
+
+import (
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gofiber/fiber/v3"
+)
+
+func TestRouterEndpoints(t *testing.T) {
+	app := fiber.New()
+	router := NewRouter(app)
+	ok := func(c fiber.Ctx) error { return c.SendString(c.Params("id")) }
+
+	calculate := router.Post("/calculate", ok)
+	item := router.Group("/api").Delete("/items/:id", ok)
+	page := router.Get("/docs/:section?", ok)
+
+	if got := calculate.HX().MustBuild()["hx-post"]; got != "/calculate" {
+		t.Errorf("Unexpected hx-post %v", got)
+	}
+	if got := item.HX(42).MustBuild()["hx-delete"]; got != "/api/items/42" {
+		t.Errorf("Unexpected hx-delete %v", got)
+	}
+	if got, _ := item.URL("a b"); got != "/api/items/a%20b" {
+		t.Errorf("Expected escaped parameter, got %q", got)
+	}
+	if got, _ := page.URL(""); got != "/docs" {
+		t.Errorf("Expected an empty optional parameter to be dropped, got %q", got)
+	}
+	if _, err := item.URL(); err == nil {
+		t.Errorf("Expected an error for a missing parameter")
+	}
+	if err := (Endpoint{}).HX().Err(); err == nil {
+		t.Errorf("Expected an error for an unregistered endpoint")
+	}
+	if n := len(router.Endpoints()); n != 3 {
+		t.Errorf("Expected 3 endpoints, got %d", n)
+	}
+
+	resp, err := app.Test(httptest.NewRequest(fiber.MethodDelete, "/api/items/7", nil))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.StatusCode != fiber.StatusOK {
+		t.Errorf("Expected the grouped route to be mounted, got %d", resp.StatusCode)
+	}
+}
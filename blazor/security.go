@@ -0,0 +1,190 @@
+package blazor
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/a-h/templ"
+	"github.com/gofiber/fiber/v3"
+)
+
+const (
+	defaultCSRFCookie = "blazor_csrf"
+	defaultCSRFHeader = "X-CSRF-Token"
+	defaultCSRFField  = "_csrf"
+
+	// nonce 자리에 {nonce}를 쓰면 요청마다 바뀝니다. tailwindcss.js가 style 태그를 넣으므로 style은 inline을 허용합니다.
+	defaultCSP = "default-src 'self'; script-src 'nonce-{nonce}' 'strict-dynamic'; style-src 'self' 'unsafe-inline'; object-src 'none'; base-uri 'self'"
+)
+
+// SecurityConfig는 Security 미들웨어 설정입니다.
+type SecurityConfig struct {
+	// ContentSecurityPolicy는 응답에 붙일 정책입니다. {nonce}가 요청마다의 nonce로 바뀝니다.
+	ContentSecurityPolicy string
+	// DisableCSP가 true이면 nonce도 CSP 헤더도 만들지 않습니다.
+	DisableCSP bool
+	// DisableCSRF가 true이면 CSRF 토큰을 발급하지도 검사하지도 않습니다.
+	DisableCSRF bool
+
+	// CookieName은 토큰을 담는 쿠키 이름입니다. 기본값은 "blazor_csrf"입니다.
+	CookieName string
+	// HeaderName은 htmx가 토큰을 실어 보내는 헤더입니다. 기본값은 "X-CSRF-Token"입니다.
+	HeaderName string
+	// FieldName은 JavaScript 없이 제출된 폼에서 토큰을 찾을 필드입니다. 기본값은 "_csrf"입니다.
+	FieldName string
+	// CookieSecure는 쿠키에 Secure 속성을 붙입니다. HTTPS에서는 켜야 합니다.
+	CookieSecure bool
+}
+
+type csrfState struct {
+	token  string
+	header string
+	field  string
+}
+
+type csrfKey struct{}
+
+type localSecurity struct{}
+
+// Security는 요청마다 CSP nonce를 만들어 프레임워크의 script 태그에 넣고,
+// CSRF 토큰을 쿠키로 발급해 문서의 hx-headers로 모든 htmx 요청에 실리게 합니다.
+// 토큰은 SetRenderer가 바인딩 전에 검사하며, 다른 핸들러에서는 VerifyCSRF를 부르면 됩니다.
+func Security(cfg SecurityConfig) fiber.Handler {
+	if cfg.ContentSecurityPolicy == "" {
+		cfg.ContentSecurityPolicy = defaultCSP
+	}
+	if cfg.CookieName == "" {
+		cfg.CookieName = defaultCSRFCookie
+	}
+	if cfg.HeaderName == "" {
+		cfg.HeaderName = defaultCSRFHeader
+	}
+	if cfg.FieldName == "" {
+		cfg.FieldName = defaultCSRFField
+	}
+
+	return func(c fiber.Ctx) error {
+		ctx := c.Context()
+
+		if !cfg.DisableCSP {
+			nonce := randomToken(16)
+			ctx = templ.WithNonce(ctx, nonce)
+			c.Set(fiber.HeaderContentSecurityPolicy, strings.ReplaceAll(cfg.ContentSecurityPolicy, "{nonce}", nonce))
+		}
+
+		if !cfg.DisableCSRF {
+			token := c.Cookies(cfg.CookieName)
+			if token == "" {
+				token = randomToken(32)
+				c.Cookie(&fiber.Cookie{
+					Name:     cfg.CookieName,
+					Value:    token,
+					Path:     "/",
+					HTTPOnly: true,
+					Secure:   cfg.CookieSecure,
+					SameSite: fiber.CookieSameSiteLaxMode,
+				})
+			}
+			state := &csrfState{token: token, header: cfg.HeaderName, field: cfg.FieldName}
+			ctx = context.WithValue(ctx, csrfKey{}, state)
+			c.Locals(localSecurity{}, state)
+		}
+
+		c.SetContext(ctx)
+		return c.Next()
+	}
+}
+
+// VerifyCSRF는 안전하지 않은 메서드의 요청에 올바른 CSRF 토큰이 실렸는지 확인합니다.
+// Security 미들웨어를 거치지 않은 요청은 검사하지 않습니다.
+func VerifyCSRF(c fiber.Ctx) error {
+	state, ok := c.Locals(localSecurity{}).(*csrfState)
+	if !ok {
+		return nil
+	}
+	switch c.Method() {
+	case fiber.MethodGet, fiber.MethodHead, fiber.MethodOptions, fiber.MethodTrace:
+		return nil
+	}
+
+	sent := c.Get(state.header)
+	if sent == "" {
+		sent = c.FormValue(state.field)
+	}
+	if sent == "" || subtle.ConstantTimeCompare([]byte(sent), []byte(state.token)) != 1 {
+		return fiber.NewError(fiber.StatusForbidden, "blazor: invalid csrf token")
+	}
+	return nil
+}
+
+// CSRFToken은 렌더링 중인 요청의 CSRF 토큰을 반환합니다.
+func CSRFToken(ctx context.Context) string {
+	if state, ok := ctx.Value(csrfKey{}).(*csrfState); ok {
+		return state.token
+	}
+	return ""
+}
+
+// CSRFField는 JavaScript 없이 제출되는 폼에 넣을 hidden input입니다.
+func CSRFField() templ.Component {
+	return templ.ComponentFunc(func(ctx context.Context, w io.Writer) error {
+		state, ok := ctx.Value(csrfKey{}).(*csrfState)
+		if !ok {
+			return nil
+		}
+		_, err := fmt.Fprintf(w, `<input type="hidden" name="%s" value="%s">`,
+			templ.EscapeString(state.field), templ.EscapeString(state.token))
+		return err
+	})
+}
+
+// WithCSRFHeader는 attrs의 hx-headers에 CSRF 헤더를 더합니다. 이미 있는 hx-headers는 유지합니다.
+// 기본 문서는 <body>에 이미 붙이므로, Layout.Template으로 문서를 직접 그릴 때 <body>에 씁니다.
+func WithCSRFHeader(ctx context.Context, attrs templ.Attributes) templ.Attributes {
+	state, ok := ctx.Value(csrfKey{}).(*csrfState)
+	if !ok {
+		return attrs
+	}
+
+	headers := map[string]string{}
+	if existing, ok := attrs["hx-headers"].(string); ok {
+		if err := json.Unmarshal([]byte(existing), &headers); err != nil {
+			return attrs
+		}
+	}
+	headers[state.header] = state.token
+	encoded, err := json.Marshal(headers)
+	if err != nil {
+		return attrs
+	}
+
+	merged := make(templ.Attributes, len(attrs)+1)
+	for k, v := range attrs {
+		merged[k] = v
+	}
+	merged["hx-headers"] = string(encoded)
+	return merged
+}
+
+// scriptAttrs는 nonce가 있으면 붙인 script 태그 속성을 만듭니다.
+func scriptAttrs(ctx context.Context, src string) templ.Attributes {
+	attrs := templ.Attributes{"src": src}
+	if nonce := templ.GetNonce(ctx); nonce != "" {
+		attrs["nonce"] = nonce
+	}
+	return attrs
+}
+
+func randomToken(n int) string {
+	b := make([]byte, n)
+	if _, err := rand.Read(b); err != nil {
+		panic(err)
+	}
+	return base64.RawURLEncoding.EncodeToString(b)
+}
@@ -0,0 +1,128 @@
+package blazor
+
+import (
+	"context"
+	"io"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/a-h/templ"
+	"github.com/gofiber/fiber/v3"
+)
+
+func TestSecurityNonce(t *testing.T) {
+	app := fiber.New()
+	app.Use(Security(SecurityConfig{DisableCSRF: true}))
+	app.Get("/", InitRender(templ.Raw("<main></main>"), "", ""))
+
+	resp, err := app.Test(httptest.NewRequest(fiber.MethodGet, "/", nil))
+	if err != nil {
+		t.Fatal(err)
+	}
+	body, _ := io.ReadAll(resp.Body)
+
+	policy := resp.Header.Get(fiber.HeaderContentSecurityPolicy)
+	start := strings.Index(policy, "'nonce-")
+	if start < 0 {
+		t.Fatalf("Expected a nonce in the policy: %s", policy)
+	}
+	nonce := policy[start+len("'nonce-"):]
+	nonce = nonce[:strings.Index(nonce, "'")]
+
+	for _, want := range []string{
+		`<script nonce="` + nonce + `" src="/statics/htmx.min.js"></script>`,
+		`&#34;inlineScriptNonce&#34;:&#34;` + nonce + `&#34;`,
+	} {
+		if !strings.Contains(string(body), want) {
+			t.Errorf("Expected %q in %s", want, body)
+		}
+	}
+	if resp.Header.Get(fiber.HeaderSetCookie) != "" {
+		t.Errorf("Unexpected cookie with CSRF disabled")
+	}
+}
+
+func TestSecurityCSRF(t *testing.T) {
+	app := fiber.New()
+	app.Use(Security(SecurityConfig{DisableCSP: true}))
+	app.Get("/", InitRender(templ.Raw("<main></main>"), "", ""))
+	app.Post("/", SetRenderer(
+		func(data *scopeRequest) templ.Component {
+			return templ.Raw("ok")
+		},
+		func(req *scopeRequest) (*scopeRequest, error) {
+			return req, nil
+		},
+	))
+
+	resp, err := app.Test(httptest.NewRequest(fiber.MethodGet, "/", nil))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var token string
+	for _, cookie := range resp.Cookies() {
+		if cookie.Name == defaultCSRFCookie {
+			token = cookie.Value
+		}
+	}
+	if token == "" {
+		t.Fatal("Expected a CSRF cookie")
+	}
+	body, _ := io.ReadAll(resp.Body)
+	if want := `<body hx-headers="{&#34;X-CSRF-Token&#34;:&#34;` + token + `&#34;}">`; !strings.Contains(string(body), want) {
+		t.Errorf("Expected %q in %s", want, body)
+	}
+
+	// A custom Template attaches the header itself, keeping its own hx-headers.
+	app.Get("/custom", InitLayout(templ.Raw(""), Layout{
+		BodyAttrs: templ.Attributes{"hx-headers": `{"X-App":"1"}`},
+		Template: func(layout Layout, content templ.Component) templ.Component {
+			return templ.ComponentFunc(func(ctx context.Context, w io.Writer) error {
+				io.WriteString(w, "<body")
+				if err := templ.RenderAttributes(ctx, w, WithCSRFHeader(ctx, layout.BodyAttrs)); err != nil {
+					return err
+				}
+				_, err := io.WriteString(w, "></body>")
+				return err
+			})
+		},
+	}))
+	req := httptest.NewRequest(fiber.MethodGet, "/custom", nil)
+	req.Header.Set(fiber.HeaderCookie, defaultCSRFCookie+"="+token)
+	resp, err = app.Test(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	body, _ = io.ReadAll(resp.Body)
+	if want := `<body hx-headers="{&#34;X-App&#34;:&#34;1&#34;,&#34;X-CSRF-Token&#34;:&#34;` + token + `&#34;}">`; !strings.Contains(string(body), want) {
+		t.Errorf("Expected %q in %s", want, body)
+	}
+
+	post := func(header, form string) int {
+		req := httptest.NewRequest(fiber.MethodPost, "/", strings.NewReader(form))
+		req.Header.Set(fiber.HeaderContentType, fiber.MIMEApplicationForm)
+		req.Header.Set(fiber.HeaderCookie, defaultCSRFCookie+"="+token)
+		if header != "" {
+			req.Header.Set(defaultCSRFHeader, header)
+		}
+		resp, err := app.Test(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return resp.StatusCode
+	}
+
+	if code := post("", "a=1"); code != fiber.StatusForbidden {
+		t.Errorf("Expected 403 without a token, got %d", code)
+	}
+	if code := post("wrong", "a=1"); code != fiber.StatusForbidden {
+		t.Errorf("Expected 403 with a wrong token, got %d", code)
+	}
+	if code := post(token, "a=1"); code != fiber.StatusOK {
+		t.Errorf("Expected 200 with the header token, got %d", code)
+	}
+	if code := post("", "a=1&_csrf="+token); code != fiber.StatusOK {
+		t.Errorf("Expected 200 with the form token, got %d", code)
+	}
+}
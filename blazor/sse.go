@@ -0,0 +1,225 @@
+package blazor
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/a-h/templ"
+	"github.com/gofiber/fiber/v3"
+	"github.com/snowmerak/fiber-blazor/ledis"
+)
+
+const (
+	defaultSSEEvent     = "message"
+	defaultSSEHeartbeat = 15 * time.Second
+)
+
+// LiveEvent는 SSE 스트림이 다시 렌더링하게 된 이유입니다.
+// pub/sub 메시지면 Channel과 Message가, 키 무효화면 Key가 채워지고
+// 연결 직후의 첫 렌더링이면 모두 비어 있습니다.
+type LiveEvent struct {
+	Channel string
+	Message string
+	Key     string
+}
+
+// SSEConfig는 SSE 핸들러 설정입니다.
+type SSEConfig struct {
+	// Channels는 요청마다 구독할 ledis pub/sub 채널을 돌려줍니다.
+	Channels func(c fiber.Ctx) []string
+	// Keys는 요청마다 추적할 ledis 키를 돌려줍니다. 키가 바뀌거나 지워지거나 만료되면 다시 렌더링합니다.
+	Keys func(c fiber.Ctx) []string
+	// Render는 이벤트마다 보낼 컴포넌트를 만듭니다. nil을 반환하면 이벤트를 보내지 않습니다.
+	Render func(ctx context.Context, ev LiveEvent) (templ.Component, error)
+	// Event는 SSE 이벤트 이름이며 sse-swap 값과 같아야 합니다. 기본값은 "message"입니다.
+	Event string
+	// Heartbeat는 연결 확인용 주석을 보내는 주기입니다. 기본값은 15초입니다.
+	Heartbeat time.Duration
+	// RenderOnConnect가 true이면 연결 직후 한 번 렌더링해서 보냅니다.
+	RenderOnConnect bool
+}
+
+// SSE는 ledis의 pub/sub 채널과 키 무효화를 구독해서, 바뀔 때마다 다시 렌더링한 templ 조각을
+// htmx SSE 확장(hx-ext="sse")으로 흘려보내는 핸들러를 만듭니다.
+// 클라이언트가 끊어지면 구독과 키 추적을 모두 해제합니다.
+func SSE(db *ledis.DistributedMap, cfg SSEConfig) fiber.Handler {
+	if cfg.Event == "" {
+		cfg.Event = defaultSSEEvent
+	}
+	if cfg.Heartbeat <= 0 {
+		cfg.Heartbeat = defaultSSEHeartbeat
+	}
+
+	return func(c fiber.Ctx) error {
+		var channels, keys []string
+		if cfg.Channels != nil {
+			channels = cfg.Channels(c)
+		}
+		if cfg.Keys != nil {
+			keys = cfg.Keys(c)
+		}
+
+		// 스트림은 핸들러가 반환된 뒤에 쓰이므로 fiber.Ctx 대신 필요한 값만 옮겨 둡니다.
+		ctx := WithScope(context.Background(), ScopeOf(c))
+
+		c.Set(fiber.HeaderContentType, "text/event-stream")
+		c.Set(fiber.HeaderCacheControl, "no-cache")
+		c.Set(fiber.HeaderConnection, "keep-alive")
+		c.Set("X-Accel-Buffering", "no")
+
+		return c.SendStreamWriter(func(w *bufio.Writer) {
+			stream := newLiveStream(db, channels, keys)
+			defer stream.close()
+
+			send := func(ev LiveEvent) bool {
+				component, err := cfg.Render(ctx, ev)
+				if err != nil || component == nil {
+					return true
+				}
+				var buf bytes.Buffer
+				if err := renderChecked(ctx, component, &buf); err != nil {
+					return true
+				}
+				writeSSE(w, cfg.Event, buf.String())
+				return w.Flush() == nil
+			}
+
+			if cfg.RenderOnConnect && !send(LiveEvent{}) {
+				return
+			}
+
+			heartbeat := time.NewTicker(cfg.Heartbeat)
+			defer heartbeat.Stop()
+
+			for {
+				select {
+				case ev := <-stream.events:
+					if !send(ev) {
+						return
+					}
+				case <-heartbeat.C:
+					w.WriteString(": ping\n\n")
+					if w.Flush() != nil {
+						return
+					}
+				}
+			}
+		})
+	}
+}
+
+// SSEConnect는 요소를 SSE 스트림에 연결하고 event를 받을 때마다 내용을 스왑하는 속성을 만듭니다. Layout.SSE를 켜야 확장이 로드됩니다.
+func SSEConnect(url string, event string) templ.Attributes {
+	if event == "" {
+		event = defaultSSEEvent
+	}
+	return templ.Attributes{
+		"hx-ext":      "sse",
+		"sse-connect": url,
+		"sse-swap":    event,
+	}
+}
+
+func writeSSE(w *bufio.Writer, event, data string) {
+	w.WriteString("event: " + event + "\n")
+	for _, line := range strings.Split(data, "\n") {
+		w.WriteString("data: " + strings.TrimSuffix(line, "\r") + "\n")
+	}
+	w.WriteString("\n")
+}
+
+// liveStream은 채널 구독과 키 추적을 하나의 이벤트 채널로 모읍니다.
+type liveStream struct {
+	db     *ledis.DistributedMap
+	ids    []int64
+	events chan LiveEvent
+	done   chan struct{}
+	wg     sync.WaitGroup
+
+	observer *keyObserver
+}
+
+func newLiveStream(db *ledis.DistributedMap, channels, keys []string) *liveStream {
+	s := &liveStream{
+		db:     db,
+		events: make(chan LiveEvent, 64),
+		done:   make(chan struct{}),
+	}
+
+	// Subscribe가 돌려주는 메시지에는 채널 이름이 없으므로 채널마다 따로 구독합니다.
+	for _, ch := range channels {
+		id, messages := db.Subscribe(ch)
+		s.ids = append(s.ids, id)
+		s.wg.Add(1)
+		go func(ch string, messages chan string) {
+			defer s.wg.Done()
+			for {
+				select {
+				case <-s.done:
+					return
+				case msg := <-messages:
+					s.emit(LiveEvent{Channel: ch, Message: msg})
+				}
+			}
+		}(ch, messages)
+	}
+
+	if len(keys) != 0 {
+		s.observer = &keyObserver{keys: make(chan string, 64)}
+		db.RegisterObserver(s.observer)
+		for _, key := range keys {
+			db.Track(key, s.observer)
+		}
+		s.wg.Add(1)
+		go func() {
+			defer s.wg.Done()
+			for {
+				select {
+				case <-s.done:
+					return
+				case key := <-s.observer.keys:
+					// Track은 한 번 알리고 끝나므로 다시 등록해 둡니다.
+					db.Track(key, s.observer)
+					s.emit(LiveEvent{Key: key})
+				}
+			}
+		}()
+	}
+
+	return s
+}
+
+func (s *liveStream) emit(ev LiveEvent) {
+	select {
+	case s.events <- ev:
+	case <-s.done:
+	}
+}
+
+func (s *liveStream) close() {
+	close(s.done)
+	s.wg.Wait()
+	for _, id := range s.ids {
+		s.db.Unsubscribe(id)
+	}
+	if s.observer != nil {
+		s.db.UnregisterObserver(s.observer)
+	}
+}
+
+// keyObserver는 ledis.Observer를 구현합니다.
+// NotifyObservers가 락을 잡은 채로 부르므로 절대 막히지 않아야 합니다.
+type keyObserver struct {
+	keys chan string
+}
+
+func (o *keyObserver) Invalidate(key string) {
+	select {
+	case o.keys <- key:
+	default:
+	}
+}
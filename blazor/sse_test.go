@@ -0,0 +1,90 @@
+package blazor
+
+import (
+	"context"
+	"io"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/a-h/templ"
+	"github.com/gofiber/fiber/v3"
+	"github.com/snowmerak/fiber-blazor/ledis"
+)
+
+func TestSSEStreamsRenderedFragments(t *testing.T) {
+	db := ledis.New(16)
+	defer db.Close()
+
+	app := fiber.New()
+	app.Get("/live", SSE(db, SSEConfig{
+		Channels: func(c fiber.Ctx) []string { return []string{"news"} },
+		Keys:     func(c fiber.Ctx) []string { return []string{"counter"} },
+		Render: func(ctx context.Context, ev LiveEvent) (templ.Component, error) {
+			switch {
+			case ev.Channel != "":
+				return templ.Raw("<p>" + ev.Channel + ":" + ev.Message + "</p>\n<p>" + ScopeFrom(ctx) + "</p>"), nil
+			case ev.Key != "":
+				return templ.Raw("<p>key:" + ev.Key + "</p>"), nil
+			}
+			return templ.Raw("<p>hello</p>"), nil
+		},
+		Event:           "update",
+		Heartbeat:       20 * time.Millisecond,
+		RenderOnConnect: true,
+	}))
+
+	go func() {
+		// 핸들러가 구독을 마칠 때까지 기다렸다가 발행합니다.
+		for db.PubSubNumSub("news")["news"] == 0 {
+			time.Sleep(5 * time.Millisecond)
+		}
+		db.Publish("news", "hi")
+		time.Sleep(20 * time.Millisecond)
+		db.Set("counter", "1", 0)
+		time.Sleep(20 * time.Millisecond)
+		db.Set("counter", "2", 0)
+	}()
+
+	req := httptest.NewRequest(fiber.MethodGet, "/live", nil)
+	req.Header.Set(HeaderScope, "w1")
+	resp, err := app.Test(req, fiber.TestConfig{Timeout: 300 * time.Millisecond, FailOnTimeout: false})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ct := resp.Header.Get(fiber.HeaderContentType); ct != "text/event-stream" {
+		t.Errorf("Expected text/event-stream, got %s", ct)
+	}
+	body, _ := io.ReadAll(resp.Body)
+	stream := string(body)
+
+	for _, want := range []string{
+		"event: update\ndata: <p>hello</p>\n\n",
+		"event: update\ndata: <p>news:hi</p>\ndata: <p>w1</p>\n\n",
+		"event: update\ndata: <p>key:counter</p>\n\n",
+	} {
+		if !strings.Contains(stream, want) {
+			t.Errorf("Expected stream to contain %q, got %q", want, stream)
+		}
+	}
+	if n := strings.Count(stream, "key:counter"); n != 2 {
+		t.Errorf("Expected key to be re-tracked and rendered twice, got %d", n)
+	}
+
+	// 연결이 끊기면 다음 heartbeat에서 구독이 정리되어야 합니다.
+	deadline := time.Now().Add(time.Second)
+	for db.PubSubNumSub("news")["news"] != 0 {
+		if time.Now().After(deadline) {
+			t.Fatalf("Expected subscription to be cleaned up after disconnect")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func TestSSEConnectAttrs(t *testing.T) {
+	attrs := SSEConnect("/live", "")
+	if attrs["hx-ext"] != "sse" || attrs["sse-connect"] != "/live" || attrs["sse-swap"] != "message" {
+		t.Errorf("Unexpected attrs: %v", attrs)
+	}
+}
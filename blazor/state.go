@@ -0,0 +1,166 @@
+package blazor
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/a-h/templ"
+	"github.com/gofiber/fiber/v3"
+	"github.com/snowmerak/fiber-blazor/ledis"
+)
+
+const (
+	defaultSessionCookie = "blazor_session"
+	defaultStateTTL      = 30 * time.Minute
+	stateKeyPrefix       = "blazor:state:"
+)
+
+// StatefulConfig는 Stateful 설정입니다.
+type StatefulConfig struct {
+	// TTL은 마지막 저장 뒤 세션의 상태를 유지할 시간입니다. 기본값은 30분입니다.
+	TTL time.Duration
+	// CookieName은 세션 ID를 담는 쿠키 이름입니다. 기본값은 "blazor_session"입니다.
+	CookieName string
+	// CookieSecure는 쿠키에 Secure 속성을 붙입니다. HTTPS에서는 켜야 합니다.
+	CookieSecure bool
+}
+
+// Stateful은 세션마다, 컴포넌트 인스턴스마다 S 상태를 ledis 해시에 보관합니다.
+// 세션 하나가 해시 키 하나이고, 필드는 컴포넌트 이름과 인스턴스 ID(scope)로 정해집니다.
+// 같은 인스턴스에 동시에 들어온 요청은 나중에 저장한 쪽이 이깁니다.
+type Stateful[S any] struct {
+	store stateStore
+}
+
+// NewStateful은 name 컴포넌트의 상태 저장소를 만듭니다.
+func NewStateful[S any](db *ledis.DistributedMap, name string, cfg StatefulConfig) *Stateful[S] {
+	return &Stateful[S]{store: newStateStore(db, name, cfg)}
+}
+
+// InstanceOf는 요청을 보낸 컴포넌트 인스턴스의 ID입니다. 바인더의 scope가 그대로 쓰입니다.
+func InstanceOf(c fiber.Ctx) string {
+	return ScopeOf(c)
+}
+
+// Load는 요청의 세션과 인스턴스에 저장된 상태를 읽습니다. 없으면 제로 값을 반환합니다.
+func (s *Stateful[S]) Load(c fiber.Ctx) (*S, error) {
+	state := new(S)
+	if _, err := s.store.load(c, state); err != nil {
+		return nil, err
+	}
+	return state, nil
+}
+
+// Save는 상태를 저장하고 세션의 TTL을 다시 늘립니다.
+func (s *Stateful[S]) Save(c fiber.Ctx, state *S) error {
+	return s.store.save(c, state)
+}
+
+// Delete는 요청의 인스턴스 상태를 지웁니다.
+func (s *Stateful[S]) Delete(c fiber.Ctx) error {
+	return s.store.delete(c)
+}
+
+// stateStore는 타입을 모르는 채로 상태를 JSON으로 읽고 씁니다. Stateful과 Registry가 함께 씁니다.
+type stateStore struct {
+	db   *ledis.DistributedMap
+	name string
+	cfg  StatefulConfig
+}
+
+type localSession struct{}
+
+func newStateStore(db *ledis.DistributedMap, name string, cfg StatefulConfig) stateStore {
+	if cfg.TTL <= 0 {
+		cfg.TTL = defaultStateTTL
+	}
+	if cfg.CookieName == "" {
+		cfg.CookieName = defaultSessionCookie
+	}
+	return stateStore{db: db, name: name, cfg: cfg}
+}
+
+// load는 저장된 상태를 v에 채우고, 저장된 상태가 있었는지 알려줍니다.
+func (s stateStore) load(c fiber.Ctx, v any) (bool, error) {
+	raw, ok, err := s.db.HGet(s.key(c), s.field(c))
+	if err != nil {
+		return false, fmt.Errorf("blazor: load state %q: %w", s.name, err)
+	}
+	if !ok {
+		return false, nil
+	}
+	if err := json.Unmarshal([]byte(raw), v); err != nil {
+		return false, fmt.Errorf("blazor: decode state %q: %w", s.name, err)
+	}
+	return true, nil
+}
+
+func (s stateStore) save(c fiber.Ctx, v any) error {
+	encoded, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Errorf("blazor: encode state %q: %w", s.name, err)
+	}
+	key := s.key(c)
+	if _, err := s.db.HSet(key, s.field(c), string(encoded)); err != nil {
+		return fmt.Errorf("blazor: save state %q: %w", s.name, err)
+	}
+	s.db.Expire(key, s.cfg.TTL)
+	return nil
+}
+
+func (s stateStore) delete(c fiber.Ctx) error {
+	if _, err := s.db.HDel(s.key(c), s.field(c)); err != nil {
+		return fmt.Errorf("blazor: delete state %q: %w", s.name, err)
+	}
+	return nil
+}
+
+func (s stateStore) key(c fiber.Ctx) string {
+	return stateKeyPrefix + s.session(c)
+}
+
+func (s stateStore) field(c fiber.Ctx) string {
+	return s.name + "/" + InstanceOf(c)
+}
+
+// session은 쿠키의 세션 ID를 읽고, 없으면 새로 발급합니다.
+// 한 요청 안에서 Load와 Save가 같은 ID를 쓰도록 Locals에 둡니다.
+func (s stateStore) session(c fiber.Ctx) string {
+	if id, ok := c.Locals(localSession{}).(string); ok {
+		return id
+	}
+	id := c.Cookies(s.cfg.CookieName)
+	if id == "" {
+		id = randomToken(32)
+		c.Cookie(&fiber.Cookie{
+			Name:     s.cfg.CookieName,
+			Value:    id,
+			Path:     "/",
+			HTTPOnly: true,
+			Secure:   s.cfg.CookieSecure,
+			SameSite: fiber.CookieSameSiteLaxMode,
+		})
+	}
+	c.Locals(localSession{}, id)
+	return id
+}
+
+// SetStatefulRenderer는 transform 전에 인스턴스 상태를 읽고, transform이 성공하면 바뀐 상태를 저장하는 SetRenderer입니다.
+// 컴포넌트는 .Scope(binder.Scope())로 인스턴스 ID를 실어 보내야 인스턴스마다 상태가 나뉩니다.
+func SetStatefulRenderer[T, S, V any](store *Stateful[S], componentFunc func(data *V) templ.Component, transform func(req *T, state *S) (*V, error), opts ...RendererOption) fiber.Handler {
+	return newRenderer(componentFunc, func(c fiber.Ctx, req *T) (*Result[V], error) {
+		state, err := store.Load(c)
+		if err != nil {
+			return nil, fiber.NewError(fiber.StatusInternalServerError, err.Error())
+		}
+		data, err := transform(req, state)
+		if err != nil {
+			return nil, err
+		}
+		if err := store.Save(c, state); err != nil {
+			return nil, fiber.NewError(fiber.StatusInternalServerError, err.Error())
+		}
+		return Reply(data), nil
+	}, opts)
+}
@@ -0,0 +1,82 @@
+package blazor
+
+import (
+	"io"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/a-h/templ"
+	"github.com/gofiber/fiber/v3"
+	"github.com/snowmerak/fiber-blazor/ledis"
+)
+
+type counterState struct {
+	Count int
+}
+
+func TestSetStatefulRenderer(t *testing.T) {
+	db := ledis.New(0)
+	defer db.Close()
+
+	store := NewStateful[counterState](db, "counter", StatefulConfig{TTL: time.Minute})
+	app := fiber.New()
+	app.Post("/", SetStatefulRenderer(store,
+		func(data *counterState) templ.Component {
+			return templ.Raw(strconv.Itoa(data.Count))
+		},
+		func(req *scopeRequest, state *counterState) (*counterState, error) {
+			state.Count += req.A
+			return state, nil
+		},
+	))
+
+	var session string
+	post := func(scope string, a int) string {
+		req := httptest.NewRequest(fiber.MethodPost, "/", strings.NewReader("a="+strconv.Itoa(a)))
+		req.Header.Set(fiber.HeaderContentType, fiber.MIMEApplicationForm)
+		req.Header.Set(HeaderScope, scope)
+		if session != "" {
+			req.Header.Set(fiber.HeaderCookie, defaultSessionCookie+"="+session)
+		}
+		resp, err := app.Test(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, cookie := range resp.Cookies() {
+			if cookie.Name == defaultSessionCookie {
+				session = cookie.Value
+			}
+		}
+		body, _ := io.ReadAll(resp.Body)
+		return string(body)
+	}
+
+	if got := post("first", 2); got != "2" {
+		t.Errorf("Expected 2, got %q", got)
+	}
+	if session == "" {
+		t.Fatal("Expected a session cookie")
+	}
+	if got := post("first", 3); got != "5" {
+		t.Errorf("Expected the first instance to keep its state, got %q", got)
+	}
+	if got := post("second", 1); got != "1" {
+		t.Errorf("Expected the second instance to start from zero, got %q", got)
+	}
+
+	key := stateKeyPrefix + session
+	if fields, _ := db.HLen(key); fields != 2 {
+		t.Errorf("Expected two instances in the session hash, got %d", fields)
+	}
+	if ttl := db.TTL(key); ttl <= 0 || ttl > time.Minute {
+		t.Errorf("Unexpected state TTL %v", ttl)
+	}
+
+	session = ""
+	if got := post("first", 1); got != "1" {
+		t.Errorf("Expected a new session to start from zero, got %q", got)
+	}
+}
@@ -0,0 +1,216 @@
+package blazor
+
+import (
+	"fmt"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"path/filepath"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/gofiber/fiber/v3"
+)
+
+// File은 <input type="file"> 필드를 만듭니다. accept에는 허용할 MIME 타입(image/*)이나 확장자(.pdf)를 넘깁니다.
+// flazor는 *multipart.FileHeader 필드의 validate 태그에 있는 mime 규칙으로 accept를 채웁니다.
+func (b *Binding) File(name string, accept ...string) Field {
+	f := b.Field(name)
+	f.file = true
+	f.accept = strings.Join(accept, ",")
+	return f
+}
+
+// Files는 여러 파일을 고를 수 있는(multiple) 파일 필드를 만듭니다. []*multipart.FileHeader 필드에 씁니다.
+func (b *Binding) Files(name string, accept ...string) Field {
+	f := b.File(name, accept...)
+	f.multiple = true
+	return f
+}
+
+// MaxUploadSize는 요청 본문이 limit 바이트를 넘으면 바인딩 전에 413으로 거절합니다.
+// 앱 전체 한도인 fiber.Config.BodyLimit보다 크게 잡아도 그 한도를 넘을 수는 없습니다.
+func MaxUploadSize(limit int64) RendererOption {
+	if limit <= 0 {
+		panic("blazor: MaxUploadSize limit must be positive")
+	}
+	return func(cfg *rendererConfig) {
+		cfg.maxUpload = limit
+	}
+}
+
+// checkUploadSize는 MaxUploadSize 한도를 검사합니다.
+func (cfg *rendererConfig) checkUploadSize(c fiber.Ctx) error {
+	if cfg.maxUpload == 0 {
+		return nil
+	}
+	size := int64(c.Request().Header.ContentLength())
+	if size < 0 {
+		// chunked 요청은 길이 헤더가 없으므로 읽힌 본문으로 잽니다.
+		size = int64(len(c.Request().Body()))
+	}
+	if size > cfg.maxUpload {
+		return fiber.NewError(fiber.StatusRequestEntityTooLarge,
+			fmt.Sprintf("request body is larger than %s", formatSize(cfg.maxUpload)))
+	}
+	return nil
+}
+
+var fileHeaderType = reflect.TypeFor[multipart.FileHeader]()
+
+// isFileType은 t가 업로드 파일(*multipart.FileHeader나 그 슬라이스)인지 알려줍니다.
+// 중첩 구조체처럼 내려가며 검사하거나 바인딩하지 않아야 합니다.
+func isFileType(t reflect.Type) bool {
+	if t.Kind() == reflect.Slice {
+		t = t.Elem()
+	}
+	if t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	return t == fileHeaderType
+}
+
+// uploadedFiles는 *multipart.FileHeader와 []*multipart.FileHeader 필드의 파일들을 반환합니다.
+func uploadedFiles(v reflect.Value) ([]*multipart.FileHeader, bool) {
+	switch fh := v.Interface().(type) {
+	case *multipart.FileHeader:
+		if fh == nil {
+			return nil, true
+		}
+		return []*multipart.FileHeader{fh}, true
+	case []*multipart.FileHeader:
+		return fh, true
+	}
+	return nil, false
+}
+
+// checkFileRule은 maxsize, mime, maxfiles 규칙을 검사합니다. 파일 필드가 아니면 검사하지 않습니다.
+func checkFileRule(v reflect.Value, name, param string) string {
+	files, ok := uploadedFiles(v)
+	if !ok {
+		return ""
+	}
+	switch name {
+	case "maxsize":
+		limit, err := parseSize(param)
+		if err != nil {
+			return fmt.Sprintf("has an invalid maxsize rule %q", param)
+		}
+		for _, fh := range files {
+			if fh != nil && fh.Size > limit {
+				return fmt.Sprintf("must be at most %s", formatSize(limit))
+			}
+		}
+	case "mime":
+		patterns := strings.Split(param, "|")
+		for _, fh := range files {
+			if fh == nil {
+				continue
+			}
+			matched, err := matchFileType(fh, patterns)
+			if err != nil {
+				return "could not be read"
+			}
+			if !matched {
+				return "must be of type " + strings.Join(patterns, ", ")
+			}
+		}
+	case "maxfiles":
+		n, err := strconv.Atoi(param)
+		if err != nil {
+			return fmt.Sprintf("has an invalid maxfiles rule %q", param)
+		}
+		if len(files) > n {
+			return fmt.Sprintf("must have at most %d files", n)
+		}
+	}
+	return ""
+}
+
+// matchFileType은 파일 내용으로 알아낸 MIME 타입이나 파일 확장자가 patterns 중 하나와 맞는지 봅니다.
+// 내용으로 구분할 수 없는 파일(application/octet-stream, text/plain)은 요청에 적힌 Content-Type을 씁니다.
+func matchFileType(fh *multipart.FileHeader, patterns []string) (bool, error) {
+	detected, err := sniffFile(fh)
+	if err != nil {
+		return false, err
+	}
+	if detected == "application/octet-stream" || detected == "text/plain" {
+		if declared, _, err := mime.ParseMediaType(fh.Header.Get(fiber.HeaderContentType)); err == nil {
+			detected = declared
+		}
+	}
+
+	ext := strings.ToLower(filepath.Ext(fh.Filename))
+	for _, pattern := range patterns {
+		pattern = strings.ToLower(strings.TrimSpace(pattern))
+		switch {
+		case strings.HasPrefix(pattern, "."):
+			if ext == pattern {
+				return true, nil
+			}
+		case strings.HasSuffix(pattern, "/*"):
+			if strings.HasPrefix(detected, strings.TrimSuffix(pattern, "*")) {
+				return true, nil
+			}
+		case detected == pattern:
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// sniffFile은 파일 앞부분으로 MIME 타입을 알아냅니다. 매개변수(charset 등)는 뺍니다.
+func sniffFile(fh *multipart.FileHeader) (string, error) {
+	f, err := fh.Open()
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+
+	head := make([]byte, 512)
+	n, err := io.ReadFull(f, head)
+	if err != nil && err != io.EOF && err != io.ErrUnexpectedEOF {
+		return "", err
+	}
+	detected, _, _ := strings.Cut(http.DetectContentType(head[:n]), ";")
+	return detected, nil
+}
+
+var sizeUnits = []struct {
+	suffix string
+	bytes  int64
+}{
+	{"GB", 1 << 30},
+	{"MB", 1 << 20},
+	{"KB", 1 << 10},
+	{"B", 1},
+}
+
+// parseSize는 2MB, 512KB, 1024 같은 크기를 바이트로 바꿉니다. 단위는 1024 배수입니다.
+func parseSize(s string) (int64, error) {
+	s = strings.ToUpper(strings.TrimSpace(s))
+	unit := int64(1)
+	for _, u := range sizeUnits {
+		if rest, ok := strings.CutSuffix(s, u.suffix); ok {
+			s, unit = strings.TrimSpace(rest), u.bytes
+			break
+		}
+	}
+	n, err := strconv.ParseInt(s, 10, 64)
+	if err != nil || n < 0 {
+		return 0, fmt.Errorf("invalid size %q", s)
+	}
+	return n * unit, nil
+}
+
+// formatSize는 나누어떨어지는 가장 큰 단위로 크기를 씁니다.
+func formatSize(n int64) string {
+	for _, u := range sizeUnits {
+		if n >= u.bytes && n%u.bytes == 0 {
+			return strconv.FormatInt(n/u.bytes, 10) + u.suffix
+		}
+	}
+	return strconv.FormatInt(n, 10) + "B"
+}
@@ -0,0 +1,145 @@
+package blazor
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http/httptest"
+	"net/textproto"
+	"strings"
+	"testing"
+
+	"github.com/a-h/templ"
+	"github.com/gofiber/fiber/v3"
+)
+
+type uploadRequest struct {
+	Title  string                  `form:"title_x" validate:"required"`
+	Avatar *multipart.FileHeader   `form:"avatar_x" validate:"required,maxsize=1KB,mime=image/png"`
+	Docs   []*multipart.FileHeader `form:"docs_x" validate:"maxfiles=2,mime=text/*|.pdf"`
+}
+
+var pngHeader = []byte("\x89PNG\r\n\x1a\n")
+
+type uploadPart struct {
+	field, filename, contentType string
+	content                      []byte
+}
+
+func multipartBody(t *testing.T, values map[string]string, files ...uploadPart) (*bytes.Buffer, string) {
+	t.Helper()
+	var body bytes.Buffer
+	w := multipart.NewWriter(&body)
+	for k, v := range values {
+		w.WriteField(k, v)
+	}
+	for _, f := range files {
+		h := make(textproto.MIMEHeader)
+		h.Set("Content-Disposition", fmt.Sprintf(`form-data; name=%q; filename=%q`, f.field, f.filename))
+		h.Set(fiber.HeaderContentType, f.contentType)
+		part, err := w.CreatePart(h)
+		if err != nil {
+			t.Fatal(err)
+		}
+		part.Write(f.content)
+	}
+	w.Close()
+	return &body, w.FormDataContentType()
+}
+
+func TestFileFieldAttrs(t *testing.T) {
+	b := NewBinding("")
+	attrs := b.File("avatar_x", "image/png", "image/jpeg").Attrs()
+	if attrs["type"] != "file" || attrs["accept"] != "image/png,image/jpeg" || attrs["multiple"] != nil {
+		t.Errorf("Unexpected file attrs: %v", attrs)
+	}
+	attrs = b.Files("docs_x").Attrs()
+	if attrs["multiple"] != true || attrs["accept"] != nil || attrs["name"] != "docs_x" {
+		t.Errorf("Unexpected multiple file attrs: %v", attrs)
+	}
+	if attrs := b.Field("title_x").Attrs(); attrs["type"] != nil {
+		t.Errorf("Expected a plain field to have no type, got %v", attrs)
+	}
+}
+
+func TestSetRendererUpload(t *testing.T) {
+	app := fiber.New()
+	app.Post("/", SetRenderer(
+		func(data *string) templ.Component {
+			return templ.Raw(*data)
+		},
+		func(req *uploadRequest) (*string, error) {
+			out := fmt.Sprintf("%s:%s:%d", req.Title, req.Avatar.Filename, len(req.Docs))
+			return &out, nil
+		},
+		MaxUploadSize(4<<10),
+	))
+
+	post := func(body *bytes.Buffer, contentType string) (int, string) {
+		req := httptest.NewRequest(fiber.MethodPost, "/", body)
+		req.Header.Set(fiber.HeaderContentType, contentType)
+		resp, err := app.Test(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		b, _ := io.ReadAll(resp.Body)
+		return resp.StatusCode, string(b)
+	}
+
+	png := uploadPart{"avatar_x", "me.png", "image/png", pngHeader}
+	body, ct := multipartBody(t, map[string]string{"title_x": "hi"}, png,
+		uploadPart{"docs_x", "a.txt", "text/plain", []byte("hello")},
+		uploadPart{"docs_x", "b.pdf", "application/pdf", []byte("%PDF-1.4")})
+	if status, got := post(body, ct); status != fiber.StatusOK || got != "hi:me.png:2" {
+		t.Errorf("Expected the upload to bind, got %d %s", status, got)
+	}
+
+	body, ct = multipartBody(t, map[string]string{"title_x": "hi"})
+	if status, got := post(body, ct); status != fiber.StatusUnprocessableEntity || !strings.Contains(got, "avatar_x: is required") {
+		t.Errorf("Expected a missing file to fail validation, got %d %s", status, got)
+	}
+
+	// A PNG name with other content is rejected by sniffing, not by the declared type.
+	body, ct = multipartBody(t, map[string]string{"title_x": "hi"}, uploadPart{"avatar_x", "me.png", "image/png", []byte("GIF89a")})
+	if status, got := post(body, ct); status != fiber.StatusUnprocessableEntity || !strings.Contains(got, "must be of type image/png") {
+		t.Errorf("Expected a mime error, got %d %s", status, got)
+	}
+
+	big := append(append([]byte{}, pngHeader...), make([]byte, 2<<10)...)
+	body, ct = multipartBody(t, map[string]string{"title_x": "hi"}, uploadPart{"avatar_x", "me.png", "image/png", big},
+		uploadPart{"docs_x", "a.txt", "text/plain", nil}, uploadPart{"docs_x", "b.txt", "text/plain", nil}, uploadPart{"docs_x", "c.txt", "text/plain", nil})
+	status, got := post(body, ct)
+	if status != fiber.StatusUnprocessableEntity || !strings.Contains(got, "avatar_x: must be at most 1KB") || !strings.Contains(got, "docs_x: must have at most 2 files") {
+		t.Errorf("Expected size and count errors, got %d %s", status, got)
+	}
+
+	body, ct = multipartBody(t, map[string]string{"title_x": "hi"}, uploadPart{"avatar_x", "me.png", "image/png", make([]byte, 8<<10)})
+	if status, _ := post(body, ct); status != fiber.StatusRequestEntityTooLarge {
+		t.Errorf("Expected 413 above MaxUploadSize, got %d", status)
+	}
+}
+
+func TestParseSize(t *testing.T) {
+	for in, want := range map[string]int64{"512": 512, "2KB": 2 << 10, "3mb": 3 << 20, "1 GB": 1 << 30, "10B": 10} {
+		if got, err := parseSize(in); err != nil || got != want {
+			t.Errorf("parseSize(%q) = %d, %v; want %d", in, got, err, want)
+		}
+	}
+	if _, err := parseSize("2TB"); err == nil {
+		t.Errorf("Expected an error for an unknown unit")
+	}
+	if got := formatSize(1536); got != "1536B" {
+		t.Errorf("Expected 1536B, got %s", got)
+	}
+}
+
+func TestHXAttrProgress(t *testing.T) {
+	attrs := Post("/upload").Encoding(EncodingMultipart).Progress("#bar").MustBuild()
+	if attrs["data-blazor-progress"] != "#bar" || attrs["hx-encoding"] != "multipart/form-data" {
+		t.Errorf("Unexpected progress attrs: %v", attrs)
+	}
+	if Post("/upload").Progress("").Err() == nil {
+		t.Errorf("Expected an error for an empty progress selector")
+	}
+}
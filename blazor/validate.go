@@ -0,0 +1,351 @@
+package blazor
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/mail"
+	"reflect"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"unicode/utf8"
+
+	"github.com/gofiber/schema"
+)
+
+// ValidationErrors는 필드 키(바인딩 이름)별 오류 메시지 목록입니다.
+// 키는 Binding.Field에 넘기는 이름과 같아서 바로 Field에 붙일 수 있습니다.
+type ValidationErrors map[string][]string
+
+// Add는 key 필드에 메시지를 추가합니다.
+func (e ValidationErrors) Add(key, message string) {
+	e[key] = append(e[key], message)
+}
+
+// Get은 key 필드의 메시지들을 반환합니다.
+func (e ValidationErrors) Get(key string) []string {
+	return e[key]
+}
+
+func (e ValidationErrors) Error() string {
+	keys := make([]string, 0, len(e))
+	for k := range e {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	parts := make([]string, 0, len(keys))
+	for _, k := range keys {
+		parts = append(parts, k+": "+strings.Join(e[k], ", "))
+	}
+	return "validation failed: " + strings.Join(parts, "; ")
+}
+
+type errorsKey struct{}
+
+// WithErrors는 렌더링 context에 검증 오류를 담습니다.
+func WithErrors(ctx context.Context, errs ValidationErrors) context.Context {
+	return context.WithValue(ctx, errorsKey{}, errs)
+}
+
+// ErrorsFrom은 렌더링 context에 담긴 검증 오류를 반환합니다.
+func ErrorsFrom(ctx context.Context) ValidationErrors {
+	errs, _ := ctx.Value(errorsKey{}).(ValidationErrors)
+	return errs
+}
+
+// ValidatorFunc는 validate 태그의 사용자 정의 규칙입니다.
+// param은 `name=param`의 오른쪽 값이며, 반환한 오류의 메시지가 그대로 필드에 붙습니다.
+type ValidatorFunc func(value reflect.Value, param string) error
+
+var (
+	validatorsMu sync.RWMutex
+	validators   = map[string]ValidatorFunc{}
+
+	regexCache sync.Map // string -> *regexp.Regexp
+)
+
+// RegisterValidator는 validate 태그에서 쓸 규칙을 등록합니다.
+func RegisterValidator(name string, fn ValidatorFunc) {
+	validatorsMu.Lock()
+	defer validatorsMu.Unlock()
+	validators[name] = fn
+}
+
+func lookupValidator(name string) (ValidatorFunc, bool) {
+	validatorsMu.RLock()
+	defer validatorsMu.RUnlock()
+	fn, ok := validators[name]
+	return fn, ok
+}
+
+// Validate는 v의 `validate:"..."` 태그를 검사합니다. 문제가 없으면 nil을 반환합니다.
+//
+// 지원하는 규칙: required, min=N, max=N (숫자는 값, 문자열과 슬라이스는 길이),
+// len=N, minlen=N, maxlen=N, email, regex=PATTERN 그리고 RegisterValidator로 등록한 규칙.
+// 파일 필드에는 maxsize=2MB, mime=image/png|image/*|.pdf, maxfiles=N도 쓸 수 있습니다.
+// regex는 패턴에 쉼표가 들어갈 수 있으므로 항상 마지막에 둡니다.
+func Validate(v any) ValidationErrors {
+	rv := reflect.ValueOf(v)
+	for rv.Kind() == reflect.Pointer {
+		if rv.IsNil() {
+			return nil
+		}
+		rv = rv.Elem()
+	}
+	if rv.Kind() != reflect.Struct {
+		return nil
+	}
+
+	errs := ValidationErrors{}
+	validateStruct(rv, "", errs)
+	if len(errs) == 0 {
+		return nil
+	}
+	return errs
+}
+
+// validateStruct는 중첩 구조체와 구조체 슬라이스까지 내려가며, 오류 키는 바인딩 이름과 같은 점 표기입니다.
+func validateStruct(rv reflect.Value, prefix string, errs ValidationErrors) {
+	rt := rv.Type()
+	for i := range rt.NumField() {
+		sf := rt.Field(i)
+		if !sf.IsExported() {
+			continue
+		}
+		if promoted(sf) {
+			if embedded, ok := structOf(rv.Field(i)); ok {
+				validateStruct(embedded, prefix, errs)
+			}
+			continue
+		}
+		key := prefix + FieldKey(sf)
+		if !isFileType(sf.Type) {
+			validateNested(rv.Field(i), key, errs)
+		}
+
+		tag, ok := sf.Tag.Lookup("validate")
+		if !ok || tag == "" || tag == "-" {
+			continue
+		}
+		for _, rule := range splitRules(tag) {
+			if msg := checkRule(rv.Field(i), rule); msg != "" {
+				errs.Add(key, msg)
+				// 비어 있는 값에 다른 규칙 메시지까지 붙이면 혼란스럽습니다.
+				if rule == "required" {
+					break
+				}
+			}
+		}
+	}
+}
+
+func validateNested(v reflect.Value, key string, errs ValidationErrors) {
+	switch v.Kind() {
+	case reflect.Struct:
+		validateStruct(v, key+".", errs)
+	case reflect.Pointer:
+		if !v.IsNil() && v.Elem().Kind() == reflect.Struct {
+			validateStruct(v.Elem(), key+".", errs)
+		}
+	case reflect.Slice, reflect.Array:
+		for j := range v.Len() {
+			elem := v.Index(j)
+			if elem.Kind() == reflect.Pointer && !elem.IsNil() {
+				elem = elem.Elem()
+			}
+			if elem.Kind() == reflect.Struct {
+				validateStruct(elem, key+"."+strconv.Itoa(j)+".", errs)
+			}
+		}
+	}
+}
+
+// promoted는 태그 없이 임베드한 구조체 필드인지 알려줍니다.
+// Fiber 바인더처럼 그 필드들은 바깥 구조체의 필드로 취급합니다.
+func promoted(sf reflect.StructField) bool {
+	return sf.Anonymous && FieldKey(sf) == sf.Name
+}
+
+func structOf(v reflect.Value) (reflect.Value, bool) {
+	if v.Kind() == reflect.Pointer {
+		if v.IsNil() {
+			return v, false
+		}
+		v = v.Elem()
+	}
+	return v, v.Kind() == reflect.Struct
+}
+
+// FieldKey는 구조체 필드가 바인딩되는 이름(form, query, header, cookie, uri 태그 순)을 반환합니다.
+func FieldKey(sf reflect.StructField) string {
+	for _, tag := range []string{"form", "query", "header", "cookie", "uri"} {
+		if v, ok := sf.Tag.Lookup(tag); ok {
+			if name, _, _ := strings.Cut(v, ","); name != "" && name != "-" {
+				return name
+			}
+		}
+	}
+	return sf.Name
+}
+
+func splitRules(tag string) []string {
+	var rules []string
+	for tag != "" {
+		if strings.HasPrefix(tag, "regex=") {
+			return append(rules, tag)
+		}
+		rule, rest, _ := strings.Cut(tag, ",")
+		if rule = strings.TrimSpace(rule); rule != "" {
+			rules = append(rules, rule)
+		}
+		tag = rest
+	}
+	return rules
+}
+
+func checkRule(v reflect.Value, rule string) string {
+	name, param, _ := strings.Cut(rule, "=")
+	switch name {
+	case "required":
+		if v.IsZero() {
+			return "is required"
+		}
+	case "min", "max":
+		limit, err := strconv.ParseFloat(param, 64)
+		if err != nil {
+			return fmt.Sprintf("has an invalid %s rule %q", name, param)
+		}
+		return checkBound(v, name, limit)
+	case "len", "minlen", "maxlen":
+		n, err := strconv.Atoi(param)
+		if err != nil {
+			return fmt.Sprintf("has an invalid %s rule %q", name, param)
+		}
+		l, ok := lengthOf(v)
+		if !ok {
+			return ""
+		}
+		switch {
+		case name == "len" && l != n:
+			return fmt.Sprintf("must be exactly %d characters long", n)
+		case name == "minlen" && l < n:
+			return fmt.Sprintf("must be at least %d characters long", n)
+		case name == "maxlen" && l > n:
+			return fmt.Sprintf("must be at most %d characters long", n)
+		}
+	case "email":
+		if s, ok := stringOf(v); ok && s != "" {
+			if addr, err := mail.ParseAddress(s); err != nil || addr.Address != s {
+				return "must be a valid email address"
+			}
+		}
+	case "regex":
+		re, err := compileRegex(param)
+		if err != nil {
+			return fmt.Sprintf("has an invalid regex rule %q", param)
+		}
+		if s, ok := stringOf(v); ok && s != "" && !re.MatchString(s) {
+			return "has an invalid format"
+		}
+	case "maxsize", "mime", "maxfiles":
+		return checkFileRule(v, name, param)
+	default:
+		fn, ok := lookupValidator(name)
+		if !ok {
+			return fmt.Sprintf("has an unknown rule %q", name)
+		}
+		if err := fn(v, param); err != nil {
+			return err.Error()
+		}
+	}
+	return ""
+}
+
+func checkBound(v reflect.Value, name string, limit float64) string {
+	var value float64
+	switch v.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		value = float64(v.Int())
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		value = float64(v.Uint())
+	case reflect.Float32, reflect.Float64:
+		value = v.Float()
+	default:
+		l, ok := lengthOf(v)
+		if !ok {
+			return ""
+		}
+		if name == "min" && l < int(limit) {
+			return fmt.Sprintf("must be at least %d characters long", int(limit))
+		}
+		if name == "max" && l > int(limit) {
+			return fmt.Sprintf("must be at most %d characters long", int(limit))
+		}
+		return ""
+	}
+	limitText := strconv.FormatFloat(limit, 'f', -1, 64)
+	if name == "min" && value < limit {
+		return "must be at least " + limitText
+	}
+	if name == "max" && value > limit {
+		return "must be at most " + limitText
+	}
+	return ""
+}
+
+func lengthOf(v reflect.Value) (int, bool) {
+	switch v.Kind() {
+	case reflect.String:
+		return utf8.RuneCountInString(v.String()), true
+	case reflect.Slice, reflect.Array, reflect.Map:
+		return v.Len(), true
+	}
+	return 0, false
+}
+
+func stringOf(v reflect.Value) (string, bool) {
+	if v.Kind() != reflect.String {
+		return "", false
+	}
+	return v.String(), true
+}
+
+func compileRegex(pattern string) (*regexp.Regexp, error) {
+	if re, ok := regexCache.Load(pattern); ok {
+		return re.(*regexp.Regexp), nil
+	}
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return nil, err
+	}
+	regexCache.Store(pattern, re)
+	return re, nil
+}
+
+// bindErrors는 fiber 바인더의 변환 오류를 필드별 오류로 바꿉니다.
+// 필드로 돌릴 수 없는 오류면 false를 반환합니다.
+func bindErrors(err error) (ValidationErrors, bool) {
+	var multi schema.MultiError
+	if !errors.As(err, &multi) {
+		return nil, false
+	}
+
+	errs := ValidationErrors{}
+	for _, e := range multi {
+		var conv schema.ConversionError
+		var empty schema.EmptyFieldError
+		switch {
+		case errors.As(e, &conv):
+			errs.Add(conv.Key, "has an invalid value")
+		case errors.As(e, &empty):
+			errs.Add(empty.Key, "is required")
+		default:
+			return nil, false
+		}
+	}
+	return errs, true
+}
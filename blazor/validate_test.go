@@ -0,0 +1,169 @@
+package blazor
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/a-h/templ"
+	"github.com/gofiber/fiber/v3"
+)
+
+type signupRequest struct {
+	Name  string `form:"name_x" validate:"required,minlen=2,maxlen=8"`
+	Email string `form:"email_x" validate:"required,email"`
+	Age   int    `form:"age_x" validate:"min=18,max=130"`
+	Code  string `form:"code_x" validate:"regex=^[a-z]{2,3}$"`
+	Nick  string `form:"nick_x" validate:"noadmin"`
+}
+
+func init() {
+	RegisterValidator("noadmin", func(v reflect.Value, _ string) error {
+		if v.String() == "admin" {
+			return errors.New("is reserved")
+		}
+		return nil
+	})
+}
+
+func TestValidate(t *testing.T) {
+	ok := signupRequest{Name: "kim", Email: "kim@example.com", Age: 20, Code: "ab", Nick: "k"}
+	if errs := Validate(&ok); errs != nil {
+		t.Errorf("Expected no errors, got %v", errs)
+	}
+
+	bad := signupRequest{Name: "k", Email: "nope", Age: 3, Code: "ABCD", Nick: "admin"}
+	errs := Validate(&bad)
+	expected := map[string]string{
+		"name_x":  "must be at least 2 characters long",
+		"email_x": "must be a valid email address",
+		"age_x":   "must be at least 18",
+		"code_x":  "has an invalid format",
+		"nick_x":  "is reserved",
+	}
+	for key, msg := range expected {
+		if got := errs.Get(key); len(got) != 1 || got[0] != msg {
+			t.Errorf("Expected %s to be %q, got %v", key, msg, got)
+		}
+	}
+
+	errs = Validate(&signupRequest{Age: 18})
+	if got := errs.Get("name_x"); len(got) != 1 || got[0] != "is required" {
+		t.Errorf("Expected name_x to be required, got %v", got)
+	}
+}
+
+func TestBindingCarriesErrors(t *testing.T) {
+	ctx := WithErrors(WithScope(context.Background(), "left"), ValidationErrors{"name_x": {"is required"}})
+	b := BindingFrom(ctx)
+	f := b.Field("name_x")
+	if !f.Invalid() || f.Message() != "is required" {
+		t.Errorf("Expected field error, got %+v", f)
+	}
+	if f.Attrs()["aria-invalid"] != "true" {
+		t.Errorf("Expected aria-invalid attribute")
+	}
+	if b.Field("email_x").Invalid() {
+		t.Errorf("Expected email_x to be valid")
+	}
+}
+
+func TestSetRendererValidation(t *testing.T) {
+	app := fiber.New()
+	handler := func(opts ...RendererOption) fiber.Handler {
+		return SetRenderer(
+			func(data *string) templ.Component {
+				return templ.Raw(*data)
+			},
+			func(req *signupRequest) (*string, error) {
+				if req.Name == "taken" {
+					return nil, ValidationErrors{"name_x": {"is already taken"}}
+				}
+				return &req.Name, nil
+			},
+			opts...,
+		)
+	}
+	app.Post("/plain", handler())
+	app.Post("/form", handler(OnInvalid(func(c fiber.Ctx) templ.Component {
+		return templ.ComponentFunc(func(ctx context.Context, w io.Writer) error {
+			b := BindingFrom(ctx)
+			_, err := io.WriteString(w, b.Field("name_x").ID+"="+b.Field("name_x").Message()+";"+b.Field("age_x").Message())
+			return err
+		})
+	})))
+
+	post := func(path, body string) (int, string) {
+		req := httptest.NewRequest(fiber.MethodPost, path, strings.NewReader(body))
+		req.Header.Set(fiber.HeaderContentType, fiber.MIMEApplicationForm)
+		req.Header.Set(HeaderScope, "s1")
+		resp, err := app.Test(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		b, _ := io.ReadAll(resp.Body)
+		return resp.StatusCode, string(b)
+	}
+
+	if status, body := post("/plain", "name_x=kim&email_x=kim@example.com&age_x=30"); status != 200 || body != "kim" {
+		t.Errorf("Expected 200 kim, got %d %s", status, body)
+	}
+	if status, _ := post("/plain", "name_x=kim&email_x=kim@example.com&age_x=3"); status != fiber.StatusUnprocessableEntity {
+		t.Errorf("Expected 422 without OnInvalid, got %d", status)
+	}
+	if status, body := post("/form", "email_x=kim@example.com&age_x=3"); status != 200 || body != "s1-name_x=is required;must be at least 18" {
+		t.Errorf("Unexpected invalid render: %d %s", status, body)
+	}
+	if _, body := post("/form", "name_x=kim&email_x=kim@example.com&age_x=abc"); body != "s1-name_x=;has an invalid value" {
+		t.Errorf("Expected bind error on age_x, got %s", body)
+	}
+	if _, body := post("/form", "name_x=taken&email_x=kim@example.com&age_x=30"); body != "s1-name_x=is already taken;" {
+		t.Errorf("Expected transform validation error, got %s", body)
+	}
+}
+
+type signup struct {
+	Name string
+	Age  int
+}
+
+func (r *signupRequest) toSignup() signup {
+	return signup{Name: r.Name, Age: r.Age}
+}
+
+func TestSetConvertedRenderer(t *testing.T) {
+	app := fiber.New()
+	app.Post("/", SetConvertedRenderer(
+		func(data *string) templ.Component {
+			return templ.Raw(*data)
+		},
+		(*signupRequest).toSignup,
+		func(req *signup) (*string, error) {
+			out := fmt.Sprintf("%s:%d", req.Name, req.Age)
+			return &out, nil
+		},
+	))
+
+	post := func(body string) (int, string) {
+		req := httptest.NewRequest(fiber.MethodPost, "/", strings.NewReader(body))
+		req.Header.Set(fiber.HeaderContentType, fiber.MIMEApplicationForm)
+		resp, err := app.Test(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		b, _ := io.ReadAll(resp.Body)
+		return resp.StatusCode, string(b)
+	}
+
+	if status, body := post("name_x=kim&email_x=kim@example.com&age_x=30"); status != fiber.StatusOK || body != "kim:30" {
+		t.Errorf("Expected the converted request, got %d %s", status, body)
+	}
+	if status, _ := post("name_x=kim&email_x=kim@example.com&age_x=3"); status != fiber.StatusUnprocessableEntity {
+		t.Errorf("Expected the bound struct to be validated, got %d", status)
+	}
+}
@@ -0,0 +1,392 @@
+package blazor
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/a-h/templ"
+	"github.com/fasthttp/websocket"
+	"github.com/gofiber/fiber/v3"
+	"github.com/gofiber/fiber/v3/log"
+	"github.com/gofiber/schema"
+	"github.com/valyala/fasthttp"
+)
+
+// WSHub는 htmx ws 확장(hx-ext="ws")으로 들어오는 메시지를 타입별 핸들러로 보내고,
+// 연결들을 그룹으로 묶어 방송할 수 있게 합니다.
+type WSHub struct {
+	// mu는 handlers와 groups를 보호합니다. Handler를 마운트한 뒤에도 HandleWS로 핸들러를 더할 수 있습니다.
+	mu       sync.RWMutex
+	handlers map[string]wsHandler
+	groups   map[string]map[*WSConn]struct{}
+
+	// OnConnect는 업그레이드 직전에 fiber.Ctx를 쓸 수 있는 마지막 기회입니다.
+	// 세션 정보 등으로 연결 상태를 채울 때 쓰며, 오류를 반환하면 연결을 거부합니다.
+	OnConnect func(c fiber.Ctx, conn *WSConn) error
+	// OnClose는 연결이 끊긴 뒤 불립니다.
+	OnClose func(conn *WSConn)
+	// OnInvalid는 메시지가 검증에 실패했을 때 불립니다. 없으면 메시지를 버립니다.
+	OnInvalid func(ctx context.Context, conn *WSConn, name string, errs ValidationErrors)
+	// OnError는 핸들러가 오류를 반환했을 때 불립니다. 없으면 로그만 남깁니다.
+	OnError func(conn *WSConn, err error)
+	// AllowedOrigins는 same-origin 외에 연결을 허용할 Origin(https://example.com)입니다.
+	// TLS를 끝내는 프록시 뒤에서는 서버가 보는 scheme이 http라서 공개 주소를 여기에 적어야 합니다.
+	AllowedOrigins []string
+}
+
+var errWSNotConnected = errors.New("blazor: websocket is not connected yet")
+
+type wsHandler func(ctx context.Context, conn *WSConn, values map[string][]string) error
+
+// NewWSHub는 빈 WSHub를 만듭니다.
+func NewWSHub() *WSHub {
+	return &WSHub{
+		handlers: make(map[string]wsHandler),
+		groups:   make(map[string]map[*WSConn]struct{}),
+	}
+}
+
+// HandleWS는 name(ws-send 요소의 name, 없으면 id)으로 들어온 메시지를 T로 디코딩해 fn에 넘깁니다.
+// T는 보통 flazor가 만든 Binded* 구조체이며 validate 태그도 검사합니다.
+func HandleWS[T any](hub *WSHub, name string, fn func(ctx context.Context, conn *WSConn, req *T) error) {
+	handler := func(ctx context.Context, conn *WSConn, values map[string][]string) error {
+		req := new(T)
+		if err := wsDecoder.Decode(req, values); err != nil {
+			errs, ok := bindErrors(err)
+			if !ok {
+				return err
+			}
+			return errs
+		}
+		if errs := bindMaps(req, values); errs != nil {
+			return errs
+		}
+		if errs := Validate(req); errs != nil {
+			return errs
+		}
+		return fn(ctx, conn, req)
+	}
+
+	hub.mu.Lock()
+	hub.handlers[name] = handler
+	hub.mu.Unlock()
+}
+
+// handler는 name으로 등록된 핸들러를 찾습니다.
+func (h *WSHub) handler(name string) (wsHandler, bool) {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	handler, ok := h.handlers[name]
+	return handler, ok
+}
+
+var wsDecoder = func() *schema.Decoder {
+	d := schema.NewDecoder()
+	d.SetAliasTag("form")
+	d.IgnoreUnknownKeys(true)
+	return d
+}()
+
+// Handler는 WebSocket 업그레이드를 처리하는 fiber.Handler를 반환합니다.
+func (h *WSHub) Handler() fiber.Handler {
+	allowed := make(map[string]struct{}, len(h.AllowedOrigins))
+	for _, origin := range h.AllowedOrigins {
+		allowed[strings.ToLower(strings.TrimSuffix(origin, "/"))] = struct{}{}
+	}
+	upgrader := websocket.FastHTTPUpgrader{
+		CheckOrigin: func(ctx *fasthttp.RequestCtx) bool {
+			// htmx가 같은 호스트로 연결하므로 기본은 same-origin만 허용합니다.
+			origin := string(ctx.Request.Header.Peek(fiber.HeaderOrigin))
+			if origin == "" {
+				return true
+			}
+			if _, ok := allowed[strings.ToLower(origin)]; ok {
+				return true
+			}
+			scheme := "http://"
+			if ctx.IsTLS() {
+				scheme = "https://"
+			}
+			return strings.EqualFold(origin, scheme+string(ctx.Host()))
+		},
+	}
+
+	return func(c fiber.Ctx) error {
+		if !websocket.FastHTTPIsWebSocketUpgrade(c.RequestCtx()) {
+			return fiber.ErrUpgradeRequired
+		}
+
+		conn := &WSConn{hub: h, state: make(map[string]any), groups: make(map[string]struct{})}
+		if h.OnConnect != nil {
+			if err := h.OnConnect(c, conn); err != nil {
+				// OnConnect가 실패하기 전에 들어간 그룹에서도 빼야 합니다.
+				conn.leaveAll()
+				return err
+			}
+		}
+
+		err := upgrader.Upgrade(c.RequestCtx(), func(ws *websocket.Conn) {
+			// OnConnect에서 그룹에 들어간 연결은 다른 goroutine의 Broadcast에서 바로 보일 수 있습니다.
+			conn.writeMu.Lock()
+			conn.ws = ws
+			conn.writeMu.Unlock()
+			h.serve(conn, ws)
+		})
+		if err != nil {
+			// 업그레이드에 실패하면 serve가 돌지 않으므로 여기서 그룹을 정리합니다.
+			conn.leaveAll()
+		}
+		return err
+	}
+}
+
+func (h *WSHub) serve(conn *WSConn, ws *websocket.Conn) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer func() {
+		cancel()
+		conn.leaveAll()
+		ws.Close()
+		if h.OnClose != nil {
+			h.OnClose(conn)
+		}
+	}()
+
+	for {
+		_, data, err := ws.ReadMessage()
+		if err != nil {
+			return
+		}
+
+		name, scope, values, err := decodeWSMessage(data)
+		if err != nil {
+			h.fail(conn, err)
+			continue
+		}
+		handler, ok := h.handler(name)
+		if !ok {
+			h.fail(conn, fmt.Errorf("blazor: no websocket handler for %q", name))
+			continue
+		}
+
+		msgCtx := WithScope(ctx, scope)
+		if err := handler(msgCtx, conn, values); err != nil {
+			var errs ValidationErrors
+			if errors.As(err, &errs) {
+				if h.OnInvalid != nil {
+					h.OnInvalid(WithErrors(msgCtx, errs), conn, name, errs)
+				}
+				continue
+			}
+			h.fail(conn, err)
+		}
+	}
+}
+
+func (h *WSHub) fail(conn *WSConn, err error) {
+	if h.OnError != nil {
+		h.OnError(conn, err)
+		return
+	}
+	log.Errorf("blazor: websocket: %v", err)
+}
+
+// decodeWSMessage는 ws 확장이 보낸 JSON에서 핸들러 이름, scope, 폼 값을 꺼냅니다.
+func decodeWSMessage(data []byte) (name, scope string, values map[string][]string, err error) {
+	var raw map[string]json.RawMessage
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return "", "", nil, fmt.Errorf("blazor: invalid websocket message: %w", err)
+	}
+
+	var headers map[string]string
+	if h, ok := raw["HEADERS"]; ok {
+		if err := json.Unmarshal(h, &headers); err != nil {
+			return "", "", nil, fmt.Errorf("blazor: invalid websocket headers: %w", err)
+		}
+		delete(raw, "HEADERS")
+	}
+	name = headers[HeaderHXTriggerName]
+	if name == "" {
+		name = headers[HeaderHXTrigger]
+	}
+
+	values = make(map[string][]string, len(raw))
+	for k, v := range raw {
+		var single string
+		if err := json.Unmarshal(v, &single); err == nil {
+			values[k] = []string{single}
+			continue
+		}
+		var multi []string
+		if err := json.Unmarshal(v, &multi); err != nil {
+			return "", "", nil, fmt.Errorf("blazor: invalid websocket value for %q", k)
+		}
+		values[k] = multi
+	}
+	scope = sanitizeScope(headers[HeaderScope])
+	unscopeValues(scope, values)
+	return name, scope, values, nil
+}
+
+// Broadcast는 group에 속한 모든 연결에 컴포넌트들을 보냅니다.
+// 받은 쪽에서는 최상위 요소마다 id로 out-of-band 스왑됩니다.
+func (h *WSHub) Broadcast(ctx context.Context, group string, components ...templ.Component) error {
+	payload, err := renderAll(ctx, components)
+	if err != nil {
+		return err
+	}
+
+	h.mu.RLock()
+	members := make([]*WSConn, 0, len(h.groups[group]))
+	for conn := range h.groups[group] {
+		members = append(members, conn)
+	}
+	h.mu.RUnlock()
+
+	var errs []error
+	for _, conn := range members {
+		if err := conn.write(payload); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// Members는 group에 속한 연결 수를 반환합니다.
+func (h *WSHub) Members(group string) int {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	return len(h.groups[group])
+}
+
+// WSConn은 WebSocket 연결 하나와 그 연결의 상태입니다.
+type WSConn struct {
+	hub *WSHub
+	ws  *websocket.Conn
+
+	writeMu sync.Mutex
+
+	mu     sync.RWMutex
+	state  map[string]any
+	groups map[string]struct{}
+}
+
+// Set은 연결 상태에 값을 저장합니다.
+func (c *WSConn) Set(key string, value any) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.state[key] = value
+}
+
+// Get은 연결 상태에서 값을 꺼냅니다.
+func (c *WSConn) Get(key string) any {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.state[key]
+}
+
+// Send는 컴포넌트들을 렌더링해서 이 연결에만 보냅니다.
+func (c *WSConn) Send(ctx context.Context, components ...templ.Component) error {
+	payload, err := renderAll(ctx, components)
+	if err != nil {
+		return err
+	}
+	return c.write(payload)
+}
+
+// Join은 연결을 group에 넣습니다.
+func (c *WSConn) Join(group string) {
+	c.hub.mu.Lock()
+	defer c.hub.mu.Unlock()
+	if c.hub.groups[group] == nil {
+		c.hub.groups[group] = make(map[*WSConn]struct{})
+	}
+	c.hub.groups[group][c] = struct{}{}
+
+	c.mu.Lock()
+	c.groups[group] = struct{}{}
+	c.mu.Unlock()
+}
+
+// Leave는 연결을 group에서 뺍니다.
+func (c *WSConn) Leave(group string) {
+	c.hub.mu.Lock()
+	defer c.hub.mu.Unlock()
+	c.hub.removeMember(group, c)
+
+	c.mu.Lock()
+	delete(c.groups, group)
+	c.mu.Unlock()
+}
+
+// Close는 연결을 닫습니다. 읽기 루프가 끝나면서 그룹에서도 빠집니다.
+func (c *WSConn) Close() error {
+	c.writeMu.Lock()
+	defer c.writeMu.Unlock()
+	if c.ws == nil {
+		return errWSNotConnected
+	}
+	return c.ws.Close()
+}
+
+func (c *WSConn) leaveAll() {
+	c.hub.mu.Lock()
+	defer c.hub.mu.Unlock()
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for group := range c.groups {
+		c.hub.removeMember(group, c)
+	}
+	c.groups = make(map[string]struct{})
+}
+
+func (h *WSHub) removeMember(group string, conn *WSConn) {
+	members, ok := h.groups[group]
+	if !ok {
+		return
+	}
+	delete(members, conn)
+	if len(members) == 0 {
+		delete(h.groups, group)
+	}
+}
+
+func (c *WSConn) write(payload []byte) error {
+	c.writeMu.Lock()
+	defer c.writeMu.Unlock()
+	if c.ws == nil {
+		return errWSNotConnected
+	}
+	return c.ws.WriteMessage(websocket.TextMessage, payload)
+}
+
+func renderAll(ctx context.Context, components []templ.Component) ([]byte, error) {
+	var buf bytes.Buffer
+	for _, component := range components {
+		if err := renderChecked(ctx, component, &buf); err != nil {
+			return nil, err
+		}
+	}
+	return buf.Bytes(), nil
+}
+
+// WSConnect는 요소를 WebSocket에 연결하는 속성을 만듭니다. Layout.WS를 켜야 확장이 로드됩니다.
+func WSConnect(url string) templ.Attributes {
+	return templ.Attributes{
+		"hx-ext":     "ws",
+		"ws-connect": url,
+	}
+}
+
+// WSSend는 요소가 trigger될 때 값을 name 핸들러로 보내는 속성을 만듭니다.
+func WSSend(name string) templ.Attributes {
+	return templ.Attributes{
+		"ws-send": true,
+		"name":    name,
+	}
+}
@@ -0,0 +1,185 @@
+package blazor
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/a-h/templ"
+	"github.com/fasthttp/websocket"
+	"github.com/gofiber/fiber/v3"
+)
+
+type chatRequest struct {
+	Text string `form:"text_x" validate:"required"`
+}
+
+func startWSApp(t *testing.T, hub *WSHub) string {
+	t.Helper()
+	app := fiber.New()
+	app.Get("/ws", hub.Handler())
+
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	go app.Listener(ln, fiber.ListenConfig{DisableStartupMessage: true})
+	t.Cleanup(func() { app.Shutdown() })
+	return "ws://" + ln.Addr().String() + "/ws"
+}
+
+func dialWS(t *testing.T, url string) *websocket.Conn {
+	t.Helper()
+	conn, _, err := websocket.DefaultDialer.Dial(url, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { conn.Close() })
+	return conn
+}
+
+func readWS(t *testing.T, conn *websocket.Conn) string {
+	t.Helper()
+	conn.SetReadDeadline(time.Now().Add(2 * time.Second))
+	_, data, err := conn.ReadMessage()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(data)
+}
+
+func TestWSHubDispatchAndBroadcast(t *testing.T) {
+	hub := NewWSHub()
+	hub.OnConnect = func(c fiber.Ctx, conn *WSConn) error {
+		conn.Set("user", c.Query("user"))
+		conn.Join("room")
+		return nil
+	}
+	hub.OnInvalid = func(ctx context.Context, conn *WSConn, name string, errs ValidationErrors) {
+		conn.Send(ctx, templ.Raw(`<p id="`+BindingFrom(ctx).ID("error").ID+`">`+name+": "+BindingFrom(ctx).Field("text_x").Message()+`</p>`))
+	}
+	HandleWS(hub, "chat", func(ctx context.Context, conn *WSConn, req *chatRequest) error {
+		msg := conn.Get("user").(string) + ": " + req.Text
+		return hub.Broadcast(ctx, "room", Compose(nil).OOBSwap(NewBinding("").ID("log"), SwapBeforeEnd, templ.Raw("<li>"+msg+"</li>")))
+	})
+
+	url := startWSApp(t, hub)
+	alice := dialWS(t, url+"?user=alice")
+	bob := dialWS(t, url+"?user=bob")
+
+	deadline := time.Now().Add(time.Second)
+	for hub.Members("room") != 2 {
+		if time.Now().After(deadline) {
+			t.Fatalf("Expected two members, got %d", hub.Members("room"))
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+
+	err := alice.WriteMessage(websocket.TextMessage, []byte(`{"text_x":"hi","HEADERS":{"HX-Trigger-Name":"chat","X-Blazor-Scope":"c1"}}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `<div id="c1-log" hx-swap-oob="beforeend"><li>alice: hi</li></div>`
+	for _, conn := range []*websocket.Conn{alice, bob} {
+		if got := readWS(t, conn); got != want {
+			t.Errorf("Expected %s, got %s", want, got)
+		}
+	}
+
+	bob.WriteMessage(websocket.TextMessage, []byte(`{"text_x":"","HEADERS":{"HX-Trigger-Name":"chat"}}`))
+	if got := readWS(t, bob); got != `<p id="error">chat: is required</p>` {
+		t.Errorf("Unexpected invalid response: %s", got)
+	}
+
+	// Handlers can be added while connections are being served.
+	HandleWS(hub, "ping", func(ctx context.Context, conn *WSConn, req *chatRequest) error {
+		return conn.Send(ctx, templ.Raw(`<p id="pong">`+req.Text+`</p>`))
+	})
+	bob.WriteMessage(websocket.TextMessage, []byte(`{"text_x":"late","HEADERS":{"HX-Trigger-Name":"ping"}}`))
+	if got := readWS(t, bob); got != `<p id="pong">late</p>` {
+		t.Errorf("Unexpected response from a late handler: %s", got)
+	}
+
+	bob.Close()
+	deadline = time.Now().Add(time.Second)
+	for hub.Members("room") != 1 {
+		if time.Now().After(deadline) {
+			t.Fatalf("Expected closed connection to leave the group")
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
+
+func TestWSHubOrigins(t *testing.T) {
+	hub := NewWSHub()
+	hub.AllowedOrigins = []string{"https://app.example.com"}
+	hub.OnConnect = func(c fiber.Ctx, conn *WSConn) error {
+		conn.Join("room")
+		if c.Query("fail") != "" {
+			return fiber.ErrForbidden
+		}
+		return nil
+	}
+	url := startWSApp(t, hub)
+
+	dial := func(query, origin string) error {
+		header := http.Header{}
+		if origin != "" {
+			header.Set(fiber.HeaderOrigin, origin)
+		}
+		conn, _, err := websocket.DefaultDialer.Dial(url+query, header)
+		if err == nil {
+			t.Cleanup(func() { conn.Close() })
+		}
+		return err
+	}
+
+	// 거절된 연결은 OnConnect에서 들어간 그룹에 남지 않습니다.
+	if err := dial("", "https://evil.example.com"); err == nil {
+		t.Errorf("Expected a foreign origin to be rejected")
+	}
+	if err := dial("?fail=1", ""); err == nil {
+		t.Errorf("Expected OnConnect to reject the connection")
+	}
+	if n := hub.Members("room"); n != 0 {
+		t.Errorf("Expected rejected connections to leave their groups, got %d members", n)
+	}
+
+	if err := dial("", "https://app.example.com"); err != nil {
+		t.Errorf("Expected an allowed origin to connect, got %v", err)
+	}
+	if err := dial("", "http://"+strings.TrimPrefix(strings.TrimSuffix(url, "/ws"), "ws://")); err != nil {
+		t.Errorf("Expected the same origin to connect, got %v", err)
+	}
+}
+
+func TestDecodeWSMessage(t *testing.T) {
+	name, scope, values, err := decodeWSMessage([]byte(`{"tags":["a","b"],"title":"x","HEADERS":{"HX-Trigger":"form-1"}}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if name != "form-1" || scope != "" {
+		t.Errorf("Unexpected name/scope: %q %q", name, scope)
+	}
+	if strings.Join(values["tags"], ",") != "a,b" || values["title"][0] != "x" {
+		t.Errorf("Unexpected values: %v", values)
+	}
+	if _, ok := values["HEADERS"]; ok {
+		t.Errorf("Expected HEADERS to be removed from values")
+	}
+
+	_, scope, values, err = decodeWSMessage([]byte(`{"left:title":"l","right:title":"r","_csrf":"t","HEADERS":{"HX-Trigger":"form-1","X-Blazor-Scope":"right"}}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if scope != "right" || len(values) != 2 || values["title"][0] != "r" || values["_csrf"][0] != "t" {
+		t.Errorf("Expected only the right instance's values unscoped, got %q %v", scope, values)
+	}
+
+	if _, _, _, err := decodeWSMessage([]byte(`{"n":1}`)); err == nil {
+		t.Errorf("Expected error for non-string value")
+	}
+}
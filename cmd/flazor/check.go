@@ -0,0 +1,808 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"go/ast"
+	goparser "go/parser"
+	"go/token"
+	"go/types"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/a-h/templ/generator"
+	"github.com/a-h/templ/parser/v2"
+)
+
+// Kinds of problems reported by flazor check.
+const (
+	// checkUnusedField: a binder field is never spread onto an element, so the form never submits it.
+	checkUnusedField = "unused-field"
+	// checkMissingTarget: an hx-target or hx-include selector matches no element.
+	checkMissingTarget = "missing-target"
+	// checkDuplicateID: one component renders the same ID twice.
+	checkDuplicateID = "duplicate-id"
+)
+
+// Exit codes of flazor check, so CI can tell findings from a broken run.
+const (
+	checkExitClean    = 0
+	checkExitFindings = 1
+	checkExitError    = 2
+)
+
+// finding is one problem, positioned in the .templ source.
+type finding struct {
+	File    string `json:"file"`
+	Line    int    `json:"line"`
+	Column  int    `json:"column"`
+	Kind    string `json:"kind"`
+	Message string `json:"message"`
+}
+
+// runCheck implements `flazor check` and returns the process exit code.
+func runCheck(args []string, stdout, stderr io.Writer) int {
+	flags := flag.NewFlagSet("check", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	asJSON := flags.Bool("json", false, "print findings as a JSON array")
+	if err := flags.Parse(args); err != nil {
+		return checkExitError
+	}
+	root := "."
+	if flags.NArg() > 1 {
+		fmt.Fprintln(stderr, "usage: flazor check [-json] [dir]")
+		return checkExitError
+	}
+	if flags.NArg() == 1 {
+		root = flags.Arg(0)
+	}
+
+	cfg, err := loadConfig(root)
+	if err != nil {
+		fmt.Fprintf(stderr, "Error: %v\n", err)
+		return checkExitError
+	}
+	findings, err := checkTemplates(root, cfg)
+	if err != nil {
+		fmt.Fprintf(stderr, "Error: %v\n", err)
+		return checkExitError
+	}
+
+	if *asJSON {
+		enc := json.NewEncoder(stdout)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(findings); err != nil {
+			fmt.Fprintf(stderr, "Error: %v\n", err)
+			return checkExitError
+		}
+	} else {
+		for _, f := range findings {
+			fmt.Fprintf(stdout, "%s:%d:%d: %s (%s)\n", f.File, f.Line, f.Column, f.Message, f.Kind)
+		}
+	}
+	if len(findings) > 0 {
+		if !*asJSON {
+			fmt.Fprintf(stderr, "flazor check: %d problem(s)\n", len(findings))
+		}
+		return checkExitFindings
+	}
+	return checkExitClean
+}
+
+// binderField is a field of a generated BindingOf<Type> struct.
+type binderField struct {
+	name string
+	kind fieldKind
+	elem string // binder type of nested, list and embedded fields
+}
+
+// checkTemplates compiles the .templ files of every package under root in
+// memory and checks how the generated code uses the binders from the package's
+// generated Go files. Packages are found by the same loader as generate.
+func checkTemplates(root string, cfg config) ([]finding, error) {
+	absRoot, err := filepath.Abs(root)
+	if err != nil {
+		return nil, err
+	}
+	pkgs, err := loadPackages(root, cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	binders := make(map[string]map[string][]binderField) // dir -> type -> fields
+	var templs []string
+	for _, pkg := range pkgs {
+		if errs := loadErrors(pkg); len(errs) > 0 {
+			return nil, fmt.Errorf("load %s:\n%s", pkg.PkgPath, strings.Join(errs, "\n"))
+		}
+		dir := rootPath(root, absRoot, pkg.Dir)
+		paths, err := filepath.Glob(filepath.Join(dir, "*.templ"))
+		if err != nil {
+			return nil, err
+		}
+		templs = append(templs, paths...)
+
+		binders[dir] = make(map[string][]binderField)
+		for _, f := range pkg.Syntax {
+			if isGenerated(f) {
+				collectBinders(f, binders[dir])
+			}
+		}
+	}
+
+	c := &checker{
+		binders:  binders,
+		used:     make(map[string]bool),
+		declared: make(map[string]bool),
+		literals: make(map[string]bool),
+		acquired: make(map[string]acquisition),
+	}
+	for _, path := range templs {
+		if err := c.checkFile(path); err != nil {
+			return nil, err
+		}
+	}
+	c.finish()
+
+	sort.Slice(c.findings, func(i, j int) bool {
+		a, b := c.findings[i], c.findings[j]
+		if a.File != b.File {
+			return a.File < b.File
+		}
+		if a.Line != b.Line {
+			return a.Line < b.Line
+		}
+		return a.Column < b.Column
+	})
+	if c.findings == nil {
+		c.findings = []finding{} // encode as [] rather than null
+	}
+	return c.findings, nil
+}
+
+// collectBinders records the fields of every BindingOf<Type> struct in f.
+func collectBinders(f *ast.File, into map[string][]binderField) {
+	binderName := func(expr ast.Expr) string {
+		if ident, ok := expr.(*ast.Ident); ok {
+			return strings.TrimPrefix(ident.Name, "BindingOf")
+		}
+		return ""
+	}
+
+	for _, decl := range f.Decls {
+		genDecl, ok := decl.(*ast.GenDecl)
+		if !ok || genDecl.Tok != token.TYPE {
+			continue
+		}
+		for _, spec := range genDecl.Specs {
+			typeSpec := spec.(*ast.TypeSpec)
+			structType, ok := typeSpec.Type.(*ast.StructType)
+			name, isBinder := strings.CutPrefix(typeSpec.Name.Name, "BindingOf")
+			if !ok || !isBinder {
+				continue
+			}
+
+			fields := []binderField{}
+			for _, field := range structType.Fields.List {
+				if len(field.Names) == 0 {
+					// *blazor.Binding, or an embedded BindingOf<Type> for a promoted struct.
+					if elem := binderName(field.Type); elem != "" {
+						fields = append(fields, binderField{name: "BindingOf" + elem, kind: fieldEmbedded, elem: elem})
+					}
+					continue
+				}
+
+				var bf binderField
+				switch t := field.Type.(type) {
+				case *ast.SelectorExpr:
+					bf.kind = fieldScalar
+					if t.Sel.Name == "Map" {
+						bf.kind = fieldMap
+					}
+				case *ast.Ident:
+					bf.kind, bf.elem = fieldNested, binderName(t)
+				case *ast.IndexExpr:
+					bf.kind, bf.elem = fieldList, binderName(t.Index)
+				default:
+					continue
+				}
+				for _, ident := range field.Names {
+					bf.name = ident.Name
+					fields = append(fields, bf)
+				}
+			}
+			into[name] = fields
+		}
+	}
+}
+
+type refKind int
+
+const (
+	refBinder refKind = iota // a BindingOf<Type> value
+	refList                  // a blazor.List field
+	refMap                   // a blazor.Map field
+	refField                 // a blazor.Field: a binder field, a map entry or an ID
+)
+
+// checkRef is what an expression in a component evaluates to, as far as binders go.
+type checkRef struct {
+	kind refKind
+	typ  string // binder type of a binder, or the type owning a field
+	elem string // row binder type of a list
+	// field is the owning binder field, used to tell which fields are rendered.
+	field string
+	// key identifies the element an ID belongs to, independent of how the binder
+	// was reached: "Address.Street", "CalcRequest#result".
+	key string
+	// path is the key as seen from this component's binder variables, so two
+	// different Address fields do not collide. "" when it depends on runtime
+	// values such as a list index or another scope.
+	path string
+	// root marks a binder from GetBindingOf<Type>, whose IDs render unprefixed
+	// when the component is not scoped.
+	root bool
+	// id is the literal ID an unscoped root binder renders, for "#id" selectors.
+	id string
+}
+
+// acquisition is where a component first gets a binder of some type.
+type acquisition struct {
+	dir string
+	typ string
+	pos finding
+}
+
+// selectorRef is a selector that has to match an element in the package.
+type selectorRef struct {
+	dir   string
+	key   string // binder key, or "" for a literal "#id"
+	id    string
+	label string
+	pos   finding
+}
+
+type checker struct {
+	binders  map[string]map[string][]binderField
+	used     map[string]bool // dir + "\x00" + Type.Field, for fields that are rendered
+	declared map[string]bool // dir + "\x00" + binder key, for IDs some element carries
+	literals map[string]bool // literal IDs declared anywhere in the project
+	acquired map[string]acquisition
+	refs     []selectorRef
+	findings []finding
+}
+
+var (
+	literalIDPattern   = regexp.MustCompile(`\sid="([^"]+)"`)
+	literalHXPattern   = regexp.MustCompile(`\s(hx-target|hx-include)="([^"]+)"`)
+	simpleIDSelector   = regexp.MustCompile(`^#[A-Za-z][A-Za-z0-9_-]*$`)
+	selectorMethodArgs = map[string]bool{"Target": true, "Include": true}
+)
+
+// checkFile generates the Go code of one .templ file and walks its components.
+func (c *checker) checkFile(path string) error {
+	tf, err := parser.Parse(path)
+	if err != nil {
+		return fmt.Errorf("%s: %w", path, err)
+	}
+	var buf bytes.Buffer
+	out, err := generator.Generate(tf, &buf, generator.WithFileName(path))
+	if err != nil {
+		return fmt.Errorf("%s: %w", path, err)
+	}
+	fset := token.NewFileSet()
+	f, err := goparser.ParseFile(fset, path, buf.Bytes(), 0)
+	if err != nil {
+		return fmt.Errorf("%s: generated code: %w", path, err)
+	}
+	src, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+
+	file := &templSource{path: path, src: string(src), fset: fset, sourceMap: out.SourceMap, searched: make(map[string]int)}
+	dir := filepath.Dir(path)
+	for _, decl := range f.Decls {
+		fn, ok := decl.(*ast.FuncDecl)
+		if !ok || fn.Body == nil {
+			continue
+		}
+		comp := &component{checker: c, file: file, dir: dir, name: fn.Name.Name, env: make(map[string]checkRef)}
+		comp.params(fn.Type.Params)
+		comp.walk(fn.Body, nil, false)
+	}
+	return nil
+}
+
+// finish reports what can only be decided once every template has been seen.
+func (c *checker) finish() {
+	for _, ref := range c.refs {
+		if ref.key != "" && c.declared[ref.dir+"\x00"+ref.key] {
+			continue
+		}
+		if ref.id != "" && c.literals[ref.id] {
+			continue
+		}
+		f := ref.pos
+		f.Kind = checkMissingTarget
+		f.Message = fmt.Sprintf("%s matches no element; no template renders that ID", ref.label)
+		c.findings = append(c.findings, f)
+	}
+
+	keys := make([]string, 0, len(c.acquired))
+	for key := range c.acquired {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	reported := make(map[string]bool)
+	for _, key := range keys {
+		acq := c.acquired[key]
+		c.unusedFields(acq, acq.typ, reported, make(map[string]bool))
+	}
+}
+
+// unusedFields reports the fields reachable from typ that no element renders.
+// Fields of nested binders are reported once, on their own type.
+func (c *checker) unusedFields(acq acquisition, typ string, reported, seen map[string]bool) {
+	if seen[typ] {
+		return
+	}
+	seen[typ] = true
+	for _, field := range c.binders[acq.dir][typ] {
+		switch field.kind {
+		case fieldNested, fieldList, fieldEmbedded:
+			c.unusedFields(acq, field.elem, reported, seen)
+			continue
+		}
+		key := acq.dir + "\x00" + typ + "." + field.name
+		if c.used[key] || reported[key] {
+			continue
+		}
+		reported[key] = true
+		f := acq.pos
+		f.Kind = checkUnusedField
+		f.Message = fmt.Sprintf("BindingOf%s.%s is never rendered; spread its Attrs() on an input", typ, field.name)
+		c.findings = append(c.findings, f)
+	}
+}
+
+// templSource maps positions in the generated code back to the .templ file.
+type templSource struct {
+	path      string
+	src       string
+	fset      *token.FileSet
+	sourceMap *parser.SourceMap
+	searched  map[string]int // text -> offset after its last match, so repeats advance
+}
+
+// position converts a position in the generated code. Static HTML has no source
+// map entry, so text is searched for from the last mapped line before pos.
+func (s *templSource) position(pos token.Pos, text string) finding {
+	p := s.fset.Position(pos)
+	line, col := uint32(p.Line-1), uint32(p.Column-1)
+	if src, ok := s.sourceMap.SourcePositionFromTarget(line, col); ok && text == "" {
+		return finding{File: s.path, Line: int(src.Line) + 1, Column: int(src.Col) + 1}
+	}
+
+	start := 0
+	for l := int(line); l >= 0; l-- {
+		cols, ok := s.sourceMap.TargetLinesToSource[uint32(l)]
+		if !ok || len(cols) == 0 {
+			continue
+		}
+		var last parser.Position
+		for _, src := range cols {
+			if src.Index > last.Index {
+				last = src
+			}
+		}
+		start = int(last.Index) - int(last.Col)
+		break
+	}
+	start = min(max(start, 0), len(s.src))
+	if text != "" {
+		start = max(start, s.searched[text])
+		if i := strings.Index(s.src[start:], text); i >= 0 {
+			start += i
+			s.searched[text] = start + len(text)
+		}
+	}
+	line = uint32(strings.Count(s.src[:start], "\n"))
+	col = uint32(start - (strings.LastIndex(s.src[:start], "\n") + 1))
+	return finding{File: s.path, Line: int(line) + 1, Column: int(col) + 1}
+}
+
+// component walks the generated function of one templ component.
+type component struct {
+	*checker
+	file *templSource
+	dir  string
+	name string
+	env  map[string]checkRef
+	ids  []renderedID
+}
+
+// renderedID is an ID rendered by the component, for duplicate detection.
+type renderedID struct {
+	path string
+	arms []arm
+	pos  finding
+}
+
+// arm is one branch of an if or switch statement. IDs in different arms of the
+// same statement never render together.
+type arm struct {
+	stmt  ast.Node
+	index int
+}
+
+func (comp *component) params(list *ast.FieldList) {
+	for _, field := range list.List {
+		ident, ok := field.Type.(*ast.Ident)
+		if !ok {
+			continue
+		}
+		typ, ok := strings.CutPrefix(ident.Name, "BindingOf")
+		if !ok || comp.binders[comp.dir][typ] == nil {
+			continue
+		}
+		for _, name := range field.Names {
+			comp.env[name.Name] = checkRef{kind: refBinder, typ: typ, path: "$" + name.Name}
+			comp.acquire(typ, name.Pos())
+		}
+	}
+}
+
+func (comp *component) acquire(typ string, pos token.Pos) {
+	key := comp.dir + "\x00" + typ
+	if _, ok := comp.acquired[key]; !ok {
+		comp.acquired[key] = acquisition{dir: comp.dir, typ: typ, pos: comp.file.position(pos, "")}
+	}
+}
+
+// walk inspects n, keeping track of the if/switch arms and loops it is in.
+func (comp *component) walk(n ast.Node, arms []arm, loop bool) {
+	within := func(stmt ast.Node, index int) []arm {
+		return append(slices.Clone(arms), arm{stmt: stmt, index: index})
+	}
+	ast.Inspect(n, func(n ast.Node) bool {
+		switch n := n.(type) {
+		case nil:
+			return false
+		case *ast.IfStmt:
+			if n.Init != nil {
+				comp.walk(n.Init, arms, loop)
+			}
+			comp.walk(n.Cond, arms, loop)
+			comp.walk(n.Body, within(n, 0), loop)
+			if n.Else != nil {
+				comp.walk(n.Else, within(n, 1), loop)
+			}
+			return false
+		case *ast.SwitchStmt:
+			if n.Init != nil {
+				comp.walk(n.Init, arms, loop)
+			}
+			if n.Tag != nil {
+				comp.walk(n.Tag, arms, loop)
+			}
+			for i, clause := range n.Body.List {
+				comp.walk(clause, within(n, i), loop)
+			}
+			return false
+		case *ast.TypeSwitchStmt:
+			if n.Init != nil {
+				comp.walk(n.Init, arms, loop)
+			}
+			comp.walk(n.Assign, arms, loop)
+			for i, clause := range n.Body.List {
+				comp.walk(clause, within(n, i), loop)
+			}
+			return false
+		case *ast.ForStmt:
+			comp.walk(n.Body, arms, true)
+			return false
+		case *ast.RangeStmt:
+			comp.walk(n.X, arms, loop)
+			comp.walk(n.Body, arms, true)
+			return false
+		}
+		comp.visit(n, arms, loop)
+		return true
+	})
+}
+
+func (comp *component) visit(n ast.Node, arms []arm, loop bool) {
+	switch n := n.(type) {
+	case *ast.AssignStmt:
+		if len(n.Lhs) != len(n.Rhs) {
+			return
+		}
+		for i, lhs := range n.Lhs {
+			if ident, ok := lhs.(*ast.Ident); ok {
+				if ref, ok := comp.resolve(n.Rhs[i]); ok {
+					comp.env[ident.Name] = ref
+				}
+			}
+		}
+
+	case *ast.SelectorExpr:
+		ref, ok := comp.resolve(n.X)
+		if !ok || ref.kind != refField {
+			return
+		}
+		switch n.Sel.Name {
+		case "Name":
+			comp.markUsed(ref)
+		case "ID":
+			// id={ field.ID } gives an element the ID without spreading Attrs().
+			comp.declare(ref)
+		}
+
+	case *ast.CallExpr:
+		if typ, ok := comp.getter(n); ok {
+			comp.acquire(typ, n.Pos())
+			return
+		}
+		sel, ok := n.Fun.(*ast.SelectorExpr)
+		if !ok {
+			return
+		}
+		switch {
+		case sel.Sel.Name == "WriteString" && len(n.Args) == 3:
+			comp.literalHTML(n.Args[2], arms, loop)
+		case sel.Sel.Name == "Attrs" && len(n.Args) == 0:
+			if ref, ok := comp.resolve(sel.X); ok && ref.kind == refField {
+				comp.markUsed(ref)
+				comp.declare(ref)
+				comp.render(ref.path, types.ExprString(sel.X)+".Attrs()", arms, loop, comp.file.position(n.Pos(), ""))
+			}
+		case sel.Sel.Name == "Selector" && len(n.Args) == 0:
+			if ref, ok := comp.resolve(sel.X); ok && ref.kind == refField && ref.key != "" {
+				comp.refs = append(comp.refs, selectorRef{
+					dir:   comp.dir,
+					key:   ref.key,
+					id:    ref.id,
+					label: "selector " + types.ExprString(n),
+					pos:   comp.file.position(n.Pos(), ""),
+				})
+			}
+		case selectorMethodArgs[sel.Sel.Name]:
+			for _, arg := range n.Args {
+				lit, ok := arg.(*ast.BasicLit)
+				if !ok || lit.Kind != token.STRING {
+					continue
+				}
+				value, err := strconv.Unquote(lit.Value)
+				if err != nil {
+					continue
+				}
+				comp.literalSelectors(sel.Sel.Name, value, comp.file.position(lit.Pos(), ""))
+			}
+		}
+	}
+}
+
+// literalHTML records the static id, hx-target and hx-include attributes of a
+// chunk of HTML written by the generated code.
+func (comp *component) literalHTML(arg ast.Expr, arms []arm, loop bool) {
+	lit, ok := arg.(*ast.BasicLit)
+	if !ok || lit.Kind != token.STRING {
+		return
+	}
+	html, err := strconv.Unquote(lit.Value)
+	if err != nil {
+		return
+	}
+	for _, m := range literalIDPattern.FindAllStringSubmatch(html, -1) {
+		attr := strings.TrimSpace(m[0])
+		comp.literals["#"+m[1]] = true
+		comp.render("#"+m[1], attr, arms, loop, comp.file.position(lit.Pos(), attr))
+	}
+	for _, m := range literalHXPattern.FindAllStringSubmatch(html, -1) {
+		attr := strings.TrimSpace(m[0])
+		comp.literalSelectors(m[1], m[2], comp.file.position(lit.Pos(), attr))
+	}
+}
+
+// literalSelectors queues the plain "#id" parts of a selector list. Extended
+// selectors such as "closest form" or "this" cannot be checked statically.
+func (comp *component) literalSelectors(attr, value string, pos finding) {
+	for _, part := range strings.Split(value, ",") {
+		part = strings.TrimSpace(part)
+		if simpleIDSelector.MatchString(part) {
+			comp.refs = append(comp.refs, selectorRef{dir: comp.dir, id: part, label: attr + " selector " + part, pos: pos})
+		}
+	}
+}
+
+func (comp *component) markUsed(ref checkRef) {
+	if ref.field != "" {
+		comp.used[comp.dir+"\x00"+ref.typ+"."+ref.field] = true
+	}
+}
+
+func (comp *component) declare(ref checkRef) {
+	if ref.key != "" {
+		comp.declared[comp.dir+"\x00"+ref.key] = true
+	}
+	if ref.id != "" {
+		comp.literals[ref.id] = true
+	}
+}
+
+// render records an ID rendered at pos and reports it when the same ID can
+// already be on the page from this component, or when it repeats in a loop.
+func (comp *component) render(path, label string, arms []arm, loop bool, pos finding) {
+	if path == "" {
+		return
+	}
+	if loop {
+		pos.Kind = checkDuplicateID
+		pos.Message = fmt.Sprintf("%s in %s renders the same ID on every loop iteration", label, comp.name)
+		comp.findings = append(comp.findings, pos)
+		return
+	}
+	for _, prev := range comp.ids {
+		if prev.path == path && !exclusive(prev.arms, arms) {
+			pos.Kind = checkDuplicateID
+			pos.Message = fmt.Sprintf("%s in %s repeats the ID already rendered at line %d", label, comp.name, prev.pos.Line)
+			comp.findings = append(comp.findings, pos)
+			return
+		}
+	}
+	comp.ids = append(comp.ids, renderedID{path: path, arms: arms, pos: pos})
+}
+
+// exclusive reports whether a and b are in different arms of the same statement.
+func exclusive(a, b []arm) bool {
+	for i := 0; i < len(a) && i < len(b); i++ {
+		if a[i].stmt != b[i].stmt {
+			return false
+		}
+		if a[i].index != b[i].index {
+			return true
+		}
+	}
+	return false
+}
+
+// getter reports whether call is GetBindingOf<Type>() or GetBindingOf<Type>From(ctx).
+func (comp *component) getter(call *ast.CallExpr) (string, bool) {
+	ident, ok := call.Fun.(*ast.Ident)
+	if !ok {
+		return "", false
+	}
+	typ, ok := strings.CutPrefix(ident.Name, "GetBindingOf")
+	if !ok {
+		return "", false
+	}
+	typ = strings.TrimSuffix(typ, "From")
+	return typ, comp.binders[comp.dir][typ] != nil
+}
+
+// resolve evaluates expr to a binder, list, map or field where it can be
+// determined statically.
+func (comp *component) resolve(expr ast.Expr) (checkRef, bool) {
+	switch e := expr.(type) {
+	case *ast.ParenExpr:
+		return comp.resolve(e.X)
+
+	case *ast.Ident:
+		ref, ok := comp.env[e.Name]
+		return ref, ok
+
+	case *ast.SelectorExpr:
+		parent, ok := comp.resolve(e.X)
+		if !ok || parent.kind != refBinder {
+			return checkRef{}, false
+		}
+		owner, field, ok := comp.lookup(parent.typ, e.Sel.Name)
+		if !ok {
+			return checkRef{}, false
+		}
+		path := join(parent.path, field.name)
+		switch field.kind {
+		case fieldScalar:
+			return checkRef{kind: refField, typ: owner, field: field.name, key: owner + "." + field.name, path: path}, true
+		case fieldNested:
+			return checkRef{kind: refBinder, typ: field.elem, path: path}, true
+		case fieldList:
+			return checkRef{kind: refList, typ: owner, elem: field.elem, field: field.name, path: path}, true
+		case fieldMap:
+			return checkRef{kind: refMap, typ: owner, field: field.name, path: path}, true
+		case fieldEmbedded:
+			return checkRef{kind: refBinder, typ: field.elem, path: parent.path, root: parent.root}, true
+		}
+
+	case *ast.CallExpr:
+		if typ, ok := comp.getter(e); ok {
+			return checkRef{kind: refBinder, typ: typ, path: typ, root: true}, true
+		}
+		sel, ok := e.Fun.(*ast.SelectorExpr)
+		if !ok {
+			return checkRef{}, false
+		}
+		parent, ok := comp.resolve(sel.X)
+		if !ok {
+			return checkRef{}, false
+		}
+		name, isLiteral := stringArg(e)
+
+		switch {
+		case parent.kind == refBinder && sel.Sel.Name == "ID":
+			ref := checkRef{kind: refField, typ: parent.typ}
+			if isLiteral {
+				ref.key = parent.typ + "#" + name
+				ref.path = join(parent.path, "#"+name)
+				if parent.root {
+					ref.id = "#" + name
+				}
+			}
+			return ref, true
+		case parent.kind == refList && sel.Sel.Name == "At":
+			return checkRef{kind: refBinder, typ: parent.elem}, true
+		case (parent.kind == refList || parent.kind == refMap) && sel.Sel.Name == "Field":
+			return checkRef{kind: refField, typ: parent.typ, key: parent.typ + "." + parent.field, path: parent.path}, true
+		case parent.kind == refMap && sel.Sel.Name == "Key":
+			ref := checkRef{kind: refField, typ: parent.typ, field: parent.field}
+			if isLiteral {
+				ref.key = parent.typ + "." + parent.field + "." + name
+				ref.path = join(parent.path, name)
+			}
+			return ref, true
+		case parent.kind == refField && sel.Sel.Name == "In":
+			// Same element, but in another instance: not a duplicate of this one.
+			parent.path, parent.id = "", ""
+			return parent, true
+		}
+	}
+	return checkRef{}, false
+}
+
+// lookup finds a field of a binder type, including fields promoted from
+// embedded binders, and returns the type that declares it.
+func (comp *component) lookup(typ, name string) (string, binderField, bool) {
+	fields := comp.binders[comp.dir][typ]
+	for _, field := range fields {
+		if field.name == name {
+			return typ, field, true
+		}
+	}
+	for _, field := range fields {
+		if field.kind == fieldEmbedded {
+			if owner, found, ok := comp.lookup(field.elem, name); ok {
+				return owner, found, true
+			}
+		}
+	}
+	return "", binderField{}, false
+}
+
+func join(path, name string) string {
+	if path == "" {
+		return ""
+	}
+	return path + "." + name
+}
+
+// stringArg returns the single string literal argument of call, if it has one.
+func stringArg(call *ast.CallExpr) (string, bool) {
+	if len(call.Args) != 1 {
+		return "", false
+	}
+	lit, ok := call.Args[0].(*ast.BasicLit)
+	if !ok || lit.Kind != token.STRING {
+		return "", false
+	}
+	value, err := strconv.Unquote(lit.Value)
+	return value, err == nil
+}
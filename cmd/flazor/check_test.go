@@ -0,0 +1,225 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// stubBinding adds the binder types generated files refer to, to stubBlazor.
+var stubBinding = map[string]string{
+	"blazor/binding.go": `package blazor
+
+type Binding struct{}
+
+func (*Binding) ID(name string) Field { return Field{} }
+
+type Field struct{ Name, ID string }
+
+func (Field) Attrs() map[string]any { return nil }
+func (Field) Selector() string      { return "" }
+`,
+}
+
+// checkGen is the generated file of the forms fixture: Login has two fields.
+const checkGen = genHeader + `
+
+package forms
+
+import "github.com/snowmerak/fiber-blazor/blazor"
+
+type BindingOfLogin struct {
+	*blazor.Binding
+	User blazor.Field
+	Pass blazor.Field
+}
+
+func GetBindingOfLogin() BindingOfLogin { return BindingOfLogin{} }
+`
+
+// checkProject writes a fixture module with the forms package and its templates.
+func checkProject(t *testing.T, templs map[string]string) string {
+	t.Helper()
+	dir := t.TempDir()
+	writeTree(t, dir, stubBlazor)
+	writeTree(t, dir, stubBinding)
+	writeTree(t, dir, map[string]string{"forms/blazor_gen.go": checkGen})
+	files := make(map[string]string)
+	for name, src := range templs {
+		files["forms/"+name] = src
+	}
+	writeTree(t, dir, files)
+	return dir
+}
+
+func TestCheckTemplates(t *testing.T) {
+	dir := checkProject(t, map[string]string{
+		"login.templ": `package forms
+
+templ Login(items []string) {
+	{{ b := GetBindingOfLogin() }}
+	<form hx-target="#result" hx-include="#missing">
+		<input { b.User.Attrs()... }/>
+		<div id="result"></div>
+		<div id="result"></div>
+		<button hx-target={ b.ID("nowhere").Selector() }></button>
+		for _, item := range items {
+			<p id="row">{ item }</p>
+		}
+		if len(items) > 0 {
+			<span id="status"></span>
+		} else {
+			<span id="status"></span>
+		}
+	</form>
+}
+`,
+	})
+
+	findings, err := checkTemplates(dir, config{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	login := filepath.Join(dir, "forms", "login.templ")
+	want := []finding{
+		{login, 4, 10, checkUnusedField, "BindingOfLogin.Pass is never rendered; spread its Attrs() on an input"},
+		{login, 5, 28, checkMissingTarget, "hx-include selector #missing matches no element; no template renders that ID"},
+		{login, 8, 8, checkDuplicateID, `id="result" in Login repeats the ID already rendered at line 7`},
+		{login, 9, 23, checkMissingTarget, `selector b.ID("nowhere").Selector() matches no element; no template renders that ID`},
+		{login, 11, 7, checkDuplicateID, `id="row" in Login renders the same ID on every loop iteration`},
+	}
+	if len(findings) != len(want) {
+		t.Fatalf("Expected %d findings, got %d:\n%+v", len(want), len(findings), findings)
+	}
+	for i := range want {
+		if findings[i] != want[i] {
+			t.Errorf("Finding %d:\n got %+v\nwant %+v", i, findings[i], want[i])
+		}
+	}
+}
+
+func TestCheckTemplatesAcrossFiles(t *testing.T) {
+	// A selector may point at an element another template of the package renders.
+	dir := checkProject(t, map[string]string{
+		"login.templ": `package forms
+
+templ Login() {
+	{{ b := GetBindingOfLogin() }}
+	<form hx-target={ b.ID("result").Selector() }>
+		<input { b.User.Attrs()... }/>
+		<input { b.Pass.Attrs()... }/>
+	</form>
+}
+`,
+		"result.templ": `package forms
+
+templ Result() {
+	{{ b := GetBindingOfLogin() }}
+	<div { b.ID("result").Attrs()... }></div>
+}
+`,
+	})
+
+	findings, err := checkTemplates(dir, config{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(findings) != 0 {
+		t.Errorf("Expected no findings, got %+v", findings)
+	}
+}
+
+func TestRunCheck(t *testing.T) {
+	clean := checkProject(t, map[string]string{
+		"login.templ": `package forms
+
+templ Login() {
+	{{ b := GetBindingOfLogin() }}
+	<input { b.User.Attrs()... }/>
+	<input { b.Pass.Attrs()... }/>
+}
+`,
+	})
+	dirty := checkProject(t, map[string]string{
+		"login.templ": `package forms
+
+templ Login() {
+	{{ b := GetBindingOfLogin() }}
+	<input { b.User.Attrs()... }/>
+}
+`,
+	})
+	broken := t.TempDir()
+	writeTree(t, broken, map[string]string{
+		"go.mod":      testGoMod,
+		"broken/a.go": "package broken\n\nfunc (\n",
+	})
+	badTempl := checkProject(t, map[string]string{"login.templ": "package forms\n\ntempl Login() {\n\t<div>\n"})
+
+	for _, tt := range []struct {
+		name   string
+		args   []string
+		code   int
+		stdout string
+		stderr string
+	}{
+		{"clean", []string{clean}, checkExitClean, "", ""},
+		{"findings", []string{dirty}, checkExitFindings, filepath.Join(dirty, "forms", "login.templ") + ":4:10: BindingOfLogin.Pass is never rendered; spread its Attrs() on an input (unused-field)\n", "flazor check: 1 problem(s)\n"},
+		{"load error", []string{broken}, checkExitError, "", "Error: load example.com/app/broken:"},
+		{"templ error", []string{badTempl}, checkExitError, "", "login.templ"},
+		{"too many arguments", []string{clean, dirty}, checkExitError, "", "usage: flazor check"},
+		{"unknown flag", []string{"-nope"}, checkExitError, "", "flag provided but not defined"},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			var stdout, stderr bytes.Buffer
+			if code := runCheck(tt.args, &stdout, &stderr); code != tt.code {
+				t.Errorf("Exit code %d, want %d (stderr %q)", code, tt.code, stderr.String())
+			}
+			if stdout.String() != tt.stdout {
+				t.Errorf("Stdout:\n%s\nwant:\n%s", stdout.String(), tt.stdout)
+			}
+			if !strings.Contains(stderr.String(), tt.stderr) || (tt.stderr == "" && stderr.Len() > 0) {
+				t.Errorf("Stderr %q, want it to contain %q", stderr.String(), tt.stderr)
+			}
+		})
+	}
+
+	t.Run("json", func(t *testing.T) {
+		var stdout, stderr bytes.Buffer
+		if code := runCheck([]string{"-json", dirty}, &stdout, &stderr); code != checkExitFindings {
+			t.Errorf("Exit code %d, want %d", code, checkExitFindings)
+		}
+		if stderr.Len() > 0 {
+			t.Errorf("Expected nothing on stderr in JSON mode, got %q", stderr.String())
+		}
+		var got []map[string]any
+		if err := json.Unmarshal(stdout.Bytes(), &got); err != nil {
+			t.Fatalf("Expected a JSON array, got %q: %v", stdout.String(), err)
+		}
+		want := map[string]any{
+			"file":    filepath.Join(dirty, "forms", "login.templ"),
+			"line":    4.0,
+			"column":  10.0,
+			"kind":    checkUnusedField,
+			"message": "BindingOfLogin.Pass is never rendered; spread its Attrs() on an input",
+		}
+		if len(got) != 1 || len(got[0]) != len(want) {
+			t.Fatalf("Expected one finding with %d keys, got %v", len(want), got)
+		}
+		for key, value := range want {
+			if got[0][key] != value {
+				t.Errorf("%s = %v, want %v", key, got[0][key], value)
+			}
+		}
+
+		stdout.Reset()
+		if code := runCheck([]string{"-json", clean}, &stdout, &stderr); code != checkExitClean {
+			t.Errorf("Exit code %d, want %d", code, checkExitClean)
+		}
+		if got := strings.TrimSpace(stdout.String()); got != "[]" {
+			t.Errorf("Expected an empty array for a clean project, got %q", got)
+		}
+	})
+}
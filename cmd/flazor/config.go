@@ -0,0 +1,128 @@
+package main
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// configFile is the optional project config read from the project root.
+const configFile = "flazor.json"
+
+// Suffix strategies for the randomized form names in generated binders.
+const (
+	// suffixRandom draws a new suffix on every run (the default).
+	suffixRandom = "random"
+	// suffixDeterministic hashes the module path, package, type and project salt,
+	// so regenerating never changes the names.
+	suffixDeterministic = "deterministic"
+	// suffixRotate mixes a deploy identifier into the deterministic hash so
+	// names are stable within a deploy and change between deploys.
+	suffixRotate = "rotate"
+)
+
+// rotationEnv overrides suffix.rotation, typically with a release tag or commit hash.
+const rotationEnv = "FLAZOR_ROTATION"
+
+type config struct {
+	Suffix suffixConfig `json:"suffix"`
+	// Tags lists the struct tags whose names get the suffix. Defaults to form, query, header and cookie.
+	Tags []string `json:"tags"`
+	// BuildTags are passed to the package loader, like go build -tags.
+	BuildTags []string `json:"buildTags"`
+}
+
+type suffixConfig struct {
+	Strategy string `json:"strategy"`
+	Salt     string `json:"salt"`
+	Rotation string `json:"rotation"`
+}
+
+// loadConfig reads flazor.json from root. A missing file yields the defaults.
+func loadConfig(root string) (config, error) {
+	var cfg config
+	data, err := os.ReadFile(filepath.Join(root, configFile))
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return cfg, err
+	}
+	if err == nil {
+		if err := json.Unmarshal(data, &cfg); err != nil {
+			return cfg, fmt.Errorf("%s: %w", configFile, err)
+		}
+	}
+
+	if len(cfg.Tags) == 0 {
+		cfg.Tags = defaultRewriteTags
+	}
+	if cfg.Suffix.Strategy == "" {
+		cfg.Suffix.Strategy = suffixRandom
+	}
+	if rotation := os.Getenv(rotationEnv); rotation != "" {
+		cfg.Suffix.Rotation = rotation
+	}
+
+	switch cfg.Suffix.Strategy {
+	case suffixRandom:
+	case suffixDeterministic, suffixRotate:
+		if cfg.Suffix.Salt == "" {
+			return cfg, fmt.Errorf("%s: suffix.salt is required for the %s strategy", configFile, cfg.Suffix.Strategy)
+		}
+		if cfg.Suffix.Strategy == suffixRotate && cfg.Suffix.Rotation == "" {
+			return cfg, fmt.Errorf("%s: the rotate strategy needs suffix.rotation or %s", configFile, rotationEnv)
+		}
+		if strings.ContainsAny(cfg.Suffix.Rotation, "\r\n") {
+			return cfg, fmt.Errorf("%s: suffix.rotation must be a single line", configFile)
+		}
+	default:
+		return cfg, fmt.Errorf("%s: unknown suffix strategy %q (want %s, %s or %s)", configFile, cfg.Suffix.Strategy, suffixRandom, suffixDeterministic, suffixRotate)
+	}
+	return cfg, nil
+}
+
+// suffixer picks the suffix appended to the form names of one generated struct.
+type suffixer struct {
+	cfg    suffixConfig
+	module string
+}
+
+// suffix returns the suffix for typeName declared in the package at pkgPath.
+func (s suffixer) suffix(pkgPath, typeName string) string {
+	if s.cfg.Strategy == suffixRandom {
+		return randomString(4)
+	}
+
+	parts := []string{s.module, pkgPath, typeName, s.cfg.Salt}
+	if s.cfg.Strategy == suffixRotate {
+		parts = append(parts, s.cfg.Rotation)
+	}
+	sum := sha256.Sum256([]byte(strings.Join(parts, "\x00")))
+	return hex.EncodeToString(sum[:4])
+}
+
+// header describes the active strategy in generated files. The salt is never written.
+func (s suffixer) header() string {
+	if s.cfg.Strategy == suffixRotate {
+		return fmt.Sprintf("// flazor: suffix=%s rotation=%s\n", s.cfg.Strategy, s.cfg.Rotation)
+	}
+	return fmt.Sprintf("// flazor: suffix=%s\n", s.cfg.Strategy)
+}
+
+// modulePath returns the module path declared in root/go.mod, or "" outside a module.
+func modulePath(root string) string {
+	data, err := os.ReadFile(filepath.Join(root, "go.mod"))
+	if err != nil {
+		return ""
+	}
+	for _, line := range strings.Split(string(data), "\n") {
+		line = strings.TrimSpace(line)
+		if after, ok := strings.CutPrefix(line, "module "); ok {
+			return strings.TrimSpace(after)
+		}
+	}
+	return ""
+}
@@ -0,0 +1,133 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"testing"
+)
+
+func TestSuffixer(t *testing.T) {
+	hex8 := regexp.MustCompile(`^[0-9a-f]{8}$`)
+	deterministic := suffixer{cfg: suffixConfig{Strategy: suffixDeterministic, Salt: "s1"}, module: "example.com/app"}
+	rotate := func(rotation string) suffixer {
+		return suffixer{cfg: suffixConfig{Strategy: suffixRotate, Salt: "s1", Rotation: rotation}, module: "example.com/app"}
+	}
+
+	base := deterministic.suffix("forms", "Login")
+	for _, tt := range []struct {
+		name string
+		got  string
+		same bool
+	}{
+		{"same package and salt", deterministic.suffix("forms", "Login"), true},
+		{"other package", deterministic.suffix("admin", "Login"), false},
+		{"other type", deterministic.suffix("forms", "Signup"), false},
+		{"other salt", suffixer{cfg: suffixConfig{Strategy: suffixDeterministic, Salt: "s2"}, module: "example.com/app"}.suffix("forms", "Login"), false},
+		{"other module", suffixer{cfg: deterministic.cfg, module: "example.com/other"}.suffix("forms", "Login"), false},
+		{"rotation", rotate("v1").suffix("forms", "Login"), false},
+	} {
+		if !hex8.MatchString(tt.got) {
+			t.Errorf("%s: expected 8 hex digits, got %q", tt.name, tt.got)
+		}
+		if (tt.got == base) != tt.same {
+			t.Errorf("%s: suffix %q against %q, want same=%v", tt.name, tt.got, base, tt.same)
+		}
+	}
+
+	if a, b := rotate("v1").suffix("forms", "Login"), rotate("v1").suffix("forms", "Login"); a != b {
+		t.Errorf("Expected a rotation to be stable, got %q and %q", a, b)
+	}
+	if a, b := rotate("v1").suffix("forms", "Login"), rotate("v2").suffix("forms", "Login"); a == b {
+		t.Errorf("Expected a new rotation to change the suffix, got %q twice", a)
+	}
+
+	random := suffixer{cfg: suffixConfig{Strategy: suffixRandom}}
+	seen := make(map[string]bool)
+	for range 8 {
+		s := random.suffix("forms", "Login")
+		if !hex8.MatchString(s) {
+			t.Errorf("Expected a random suffix of 8 hex digits, got %q", s)
+		}
+		seen[s] = true
+	}
+	if len(seen) < 2 {
+		t.Errorf("Expected random suffixes to differ, got %v", seen)
+	}
+}
+
+func TestSuffixerHeader(t *testing.T) {
+	for _, tt := range []struct {
+		cfg  suffixConfig
+		want string
+	}{
+		{suffixConfig{Strategy: suffixRandom}, "// flazor: suffix=random\n"},
+		{suffixConfig{Strategy: suffixDeterministic, Salt: "secret"}, "// flazor: suffix=deterministic\n"},
+		{suffixConfig{Strategy: suffixRotate, Salt: "secret", Rotation: "v3"}, "// flazor: suffix=rotate rotation=v3\n"},
+	} {
+		if got := (suffixer{cfg: tt.cfg}).header(); got != tt.want {
+			t.Errorf("header(%+v) = %q, want %q", tt.cfg, got, tt.want)
+		}
+	}
+}
+
+func TestLoadConfig(t *testing.T) {
+	for _, tt := range []struct {
+		name     string
+		file     string // flazor.json, or "" for none
+		env      string // FLAZOR_ROTATION
+		want     suffixConfig
+		wantTags []string
+		err      string
+	}{
+		{name: "defaults", want: suffixConfig{Strategy: suffixRandom}, wantTags: defaultRewriteTags},
+		{name: "tags", file: `{"tags":["form"]}`, want: suffixConfig{Strategy: suffixRandom}, wantTags: []string{"form"}},
+		{name: "deterministic", file: `{"suffix":{"strategy":"deterministic","salt":"s"}}`, want: suffixConfig{Strategy: suffixDeterministic, Salt: "s"}},
+		{name: "deterministic without salt", file: `{"suffix":{"strategy":"deterministic"}}`, err: "suffix.salt is required"},
+		{name: "rotate from file", file: `{"suffix":{"strategy":"rotate","salt":"s","rotation":"v1"}}`, want: suffixConfig{Strategy: suffixRotate, Salt: "s", Rotation: "v1"}},
+		{name: "rotate from env", file: `{"suffix":{"strategy":"rotate","salt":"s","rotation":"v1"}}`, env: "abc123", want: suffixConfig{Strategy: suffixRotate, Salt: "s", Rotation: "abc123"}},
+		{name: "rotate without rotation", file: `{"suffix":{"strategy":"rotate","salt":"s"}}`, err: "needs suffix.rotation or FLAZOR_ROTATION"},
+		{name: "multiline rotation", file: `{"suffix":{"strategy":"rotate","salt":"s"}}`, env: "a\nb", err: "must be a single line"},
+		{name: "unknown strategy", file: `{"suffix":{"strategy":"hourly"}}`, err: `unknown suffix strategy "hourly"`},
+		{name: "malformed", file: `{`, err: configFile},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			if tt.file != "" {
+				if err := os.WriteFile(filepath.Join(dir, configFile), []byte(tt.file), 0o644); err != nil {
+					t.Fatal(err)
+				}
+			}
+			t.Setenv(rotationEnv, tt.env)
+
+			cfg, err := loadConfig(dir)
+			if tt.err != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.err) {
+					t.Fatalf("Expected an error containing %q, got %v", tt.err, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if cfg.Suffix != tt.want {
+				t.Errorf("Suffix = %+v, want %+v", cfg.Suffix, tt.want)
+			}
+			if tt.wantTags != nil && strings.Join(cfg.Tags, ",") != strings.Join(tt.wantTags, ",") {
+				t.Errorf("Tags = %v, want %v", cfg.Tags, tt.wantTags)
+			}
+		})
+	}
+}
+
+func TestModulePath(t *testing.T) {
+	dir := t.TempDir()
+	if got := modulePath(dir); got != "" {
+		t.Errorf("Expected no module outside a module, got %q", got)
+	}
+	writeTree(t, dir, map[string]string{"go.mod": "// comment\nmodule example.com/app\n\ngo 1.25\n"})
+	if got := modulePath(dir); got != "example.com/app" {
+		t.Errorf("Expected example.com/app, got %q", got)
+	}
+}
@@ -0,0 +1,233 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"os/exec"
+	"os/signal"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/a-h/templ/cmd/templ/generatecmd"
+	"github.com/fsnotify/fsnotify"
+)
+
+// devDebounce groups the burst of events an editor save produces into one rebuild.
+const devDebounce = 150 * time.Millisecond
+
+// runDev implements `flazor dev`: it watches .go and .templ files, regenerates what
+// changed, and rebuilds and restarts the application with BLAZOR_DEV=1 so pages
+// served by blazor reload themselves when the new process comes up.
+func runDev(args []string) error {
+	flags := flag.NewFlagSet("dev", flag.ContinueOnError)
+	pkg := flags.String("app", ".", "package of the application to build and run")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	// Start from a fully generated tree; errors are reported but don't stop the watcher.
+	if err := run(); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+	}
+
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return err
+	}
+	defer watcher.Close()
+	if err := watchTree(watcher, "."); err != nil {
+		return err
+	}
+
+	bin := filepath.Join(os.TempDir(), fmt.Sprintf("flazor-dev-%d", os.Getpid()))
+	if runtime.GOOS == "windows" {
+		bin += ".exe"
+	}
+	app := &devApp{pkg: *pkg, args: flags.Args(), bin: bin}
+	defer os.Remove(app.bin)
+	defer app.stop()
+	app.restart()
+
+	stop := make(chan os.Signal, 1)
+	signal.Notify(stop, os.Interrupt, syscall.SIGTERM)
+
+	changed := make(map[string]fsnotify.Op)
+	debounce := time.NewTimer(time.Hour)
+	debounce.Stop()
+
+	fmt.Println("Watching for changes...")
+	for {
+		select {
+		case <-stop:
+			return nil
+		case err := <-watcher.Errors:
+			fmt.Fprintf(os.Stderr, "Warning: watcher: %v\n", err)
+		case ev := <-watcher.Events:
+			if ev.Has(fsnotify.Create) {
+				if info, err := os.Stat(ev.Name); err == nil && info.IsDir() {
+					if err := watchTree(watcher, ev.Name); err != nil {
+						fmt.Fprintf(os.Stderr, "Warning: watcher: %v\n", err)
+					}
+					continue
+				}
+			}
+			if !isSourceFile(ev.Name) {
+				continue
+			}
+			changed[ev.Name] |= ev.Op
+			debounce.Reset(devDebounce)
+		case <-debounce.C:
+			rebuild(changed)
+			clear(changed)
+			app.restart()
+		}
+	}
+}
+
+// watchTree adds root and its subdirectories. It skips what the go tool leaves
+// out of ./..., so the generator's loader never sees it either: vendor,
+// testdata, names starting with "." or "_" and nested modules. node_modules is
+// skipped too, since it can be large and never holds Go or templ sources.
+func watchTree(watcher *fsnotify.Watcher, root string) error {
+	return filepath.Walk(root, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if !info.IsDir() {
+			return nil
+		}
+		if path != "." {
+			name := info.Name()
+			if name == "vendor" || name == "testdata" || name == "node_modules" || strings.HasPrefix(name, ".") || strings.HasPrefix(name, "_") {
+				return filepath.SkipDir
+			}
+			if _, err := os.Stat(filepath.Join(path, "go.mod")); err == nil {
+				return filepath.SkipDir
+			}
+		}
+		return watcher.Add(path)
+	})
+}
+
+// isSourceFile reports whether a change to path needs a rebuild. Generated files
+// are skipped so our own output doesn't trigger another round.
+func isSourceFile(path string) bool {
+	if strings.HasSuffix(path, "_gen.go") || strings.HasSuffix(path, "_templ.go") {
+		return false
+	}
+	return strings.HasSuffix(path, ".go") || strings.HasSuffix(path, ".templ")
+}
+
+// rebuild regenerates binders for the packages of changed .go files and the
+// templ output of changed .templ files.
+func rebuild(changed map[string]fsnotify.Op) {
+	paths := make([]string, 0, len(changed))
+	for path := range changed {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+	fmt.Printf("Changed: %s\n", strings.Join(paths, ", "))
+
+	cfg, err := loadConfig(".")
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return
+	}
+
+	// Binders are generated per package, so a change regenerates its directory.
+	dirs := make(map[string]bool)
+	var templs []string
+	for _, path := range paths {
+		_, statErr := os.Stat(path)
+		removed := errors.Is(statErr, os.ErrNotExist)
+
+		switch {
+		case strings.HasSuffix(path, ".templ"):
+			if removed {
+				os.Remove(strings.TrimSuffix(path, ".templ") + "_templ.go")
+			} else {
+				templs = append(templs, path)
+			}
+		default:
+			// The generator drops blazor_gen.go itself once a package binds nothing.
+			dirs[filepath.Dir(path)] = true
+		}
+	}
+
+	if len(dirs) > 0 {
+		if err := generateBinders(".", cfg, dirs); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: generate binders: %v\n", err)
+		}
+	}
+	if err := checkEndpoints(".", cfg); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+	}
+	for _, path := range templs {
+		if err := generatecmd.Run(context.Background(), os.Stdout, os.Stderr, []string{"-f", path}); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: templ generate %s: %v\n", path, err)
+		}
+	}
+}
+
+// devApp builds the application into a temporary binary and keeps one instance running.
+type devApp struct {
+	pkg  string
+	args []string
+	bin  string
+	cmd  *exec.Cmd
+	done chan struct{}
+}
+
+// restart rebuilds the application and replaces the running process.
+// A failed build leaves the previous process running.
+func (a *devApp) restart() {
+	build := exec.Command("go", "build", "-o", a.bin, a.pkg)
+	build.Stdout = os.Stdout
+	build.Stderr = os.Stderr
+	if err := build.Run(); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: build %s: %v\n", a.pkg, err)
+		return
+	}
+
+	a.stop()
+	cmd := exec.Command(a.bin, a.args...)
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	cmd.Env = append(os.Environ(), "BLAZOR_DEV=1") // blazor.EnvDev
+	if err := cmd.Start(); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: start %s: %v\n", a.pkg, err)
+		return
+	}
+
+	done := make(chan struct{})
+	go func() {
+		cmd.Wait()
+		close(done)
+	}()
+	a.cmd, a.done = cmd, done
+	fmt.Printf("Started %s (pid %d)\n", a.pkg, cmd.Process.Pid)
+}
+
+// stop interrupts the running process and kills it if it doesn't exit in time.
+func (a *devApp) stop() {
+	if a.cmd == nil {
+		return
+	}
+	if err := a.cmd.Process.Signal(os.Interrupt); err != nil {
+		a.cmd.Process.Kill()
+	}
+	select {
+	case <-a.done:
+	case <-time.After(5 * time.Second):
+		a.cmd.Process.Kill()
+		<-a.done
+	}
+	a.cmd, a.done = nil, nil
+}
@@ -0,0 +1,46 @@
+package main
+
+import (
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/fsnotify/fsnotify"
+)
+
+func TestWatchTree(t *testing.T) {
+	dir := t.TempDir()
+	writeTree(t, dir, map[string]string{
+		"go.mod":                    testGoMod,
+		"main.go":                   "package main\n",
+		"forms/forms.go":            "package forms\n",
+		"forms/deep/deep.go":        "package deep\n",
+		"vendor/x/x.go":             "package x\n",
+		"testdata/t.go":             "package t\n",
+		"node_modules/a/a.js":       "",
+		".git/HEAD":                 "",
+		"_scratch/s.go":             "package s\n",
+		"tools/go.mod":              "module example.com/tools\n",
+		"tools/tools.go":            "package tools\n",
+		"forms/_old/old.go":         "package old\n",
+		"forms/deep/.cache/file.go": "package cache\n",
+	})
+	t.Chdir(dir)
+
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer watcher.Close()
+	if err := watchTree(watcher, "."); err != nil {
+		t.Fatal(err)
+	}
+
+	got := watcher.WatchList()
+	slices.Sort(got)
+	want := []string{".", "forms", filepath.Join("forms", "deep")}
+	if strings.Join(got, ",") != strings.Join(want, ",") {
+		t.Errorf("Watched %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,179 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"go/ast"
+	"go/types"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"golang.org/x/tools/go/packages"
+)
+
+// routerMethods are the blazor.Router methods that return a registered Endpoint.
+var routerMethods = map[string]bool{
+	"Get":    true,
+	"Post":   true,
+	"Put":    true,
+	"Patch":  true,
+	"Delete": true,
+}
+
+// checkEndpoints fails when a template uses a blazor.Endpoint variable that is
+// never assigned from a blazor.Router registration anywhere in the project.
+// Endpoints are identified by their type-checked object, so a variable is only
+// registered by an assignment to that very variable, not by a namesake in
+// another package.
+func checkEndpoints(root string, cfg config) error {
+	absRoot, err := filepath.Abs(root)
+	if err != nil {
+		return err
+	}
+	pkgs, err := loadPackages(root, cfg)
+	if err != nil {
+		return err
+	}
+
+	registered := make(map[types.Object]bool)
+	templs := make(map[*packages.Package][]string)
+	for _, pkg := range pkgs {
+		if errs := loadErrors(pkg); len(errs) > 0 {
+			return fmt.Errorf("load %s:\n%s", pkg.PkgPath, strings.Join(errs, "\n"))
+		}
+		paths, err := filepath.Glob(filepath.Join(rootPath(root, absRoot, pkg.Dir), "*.templ"))
+		if err != nil {
+			return err
+		}
+		templs[pkg] = paths
+
+		for _, f := range pkg.Syntax {
+			if isGenerated(f) || strings.HasSuffix(pkg.Fset.File(f.Pos()).Name(), "_templ.go") {
+				continue
+			}
+			collectRegistrations(pkg.TypesInfo, f, registered)
+		}
+	}
+
+	var problems []string
+	for _, pkg := range pkgs {
+		for _, path := range templs[pkg] {
+			found, err := endpointReferences(path, pkg.Types)
+			if err != nil {
+				return err
+			}
+			for _, ref := range found {
+				if !registered[ref.obj] {
+					problems = append(problems, fmt.Sprintf("%s:%d: endpoint %s is used but never registered with a blazor.Router", path, ref.line, ref.name))
+				}
+			}
+		}
+	}
+	if len(problems) > 0 {
+		sort.Strings(problems)
+		return fmt.Errorf("unregistered endpoints:\n%s", strings.Join(problems, "\n"))
+	}
+	return nil
+}
+
+// collectRegistrations records the variables in f that are assigned, or
+// initialized, from a blazor.Router registration.
+func collectRegistrations(info *types.Info, f *ast.File, registered map[types.Object]bool) {
+	ast.Inspect(f, func(n ast.Node) bool {
+		switch n := n.(type) {
+		case *ast.ValueSpec:
+			for i, name := range n.Names {
+				if i < len(n.Values) && isRouterCall(info, n.Values[i]) {
+					registered[info.Defs[name]] = true
+				}
+			}
+		case *ast.AssignStmt:
+			if len(n.Lhs) != len(n.Rhs) {
+				return true
+			}
+			for i, lhs := range n.Lhs {
+				if !isRouterCall(info, n.Rhs[i]) {
+					continue
+				}
+				switch target := lhs.(type) {
+				case *ast.Ident:
+					registered[info.ObjectOf(target)] = true
+				case *ast.SelectorExpr:
+					registered[info.ObjectOf(target.Sel)] = true
+				}
+			}
+		}
+		return true
+	})
+}
+
+// isRouterCall reports whether expr calls one of routerMethods on a blazor.Router.
+func isRouterCall(info *types.Info, expr ast.Expr) bool {
+	call, ok := expr.(*ast.CallExpr)
+	if !ok {
+		return false
+	}
+	sel, ok := call.Fun.(*ast.SelectorExpr)
+	if !ok || !routerMethods[sel.Sel.Name] {
+		return false
+	}
+	selection, ok := info.Selections[sel]
+	return ok && selection.Kind() == types.MethodVal && isBlazorType(selection.Recv(), "Router")
+}
+
+// isBlazorType reports whether t is blazor.<name> or a pointer to it.
+func isBlazorType(t types.Type, name string) bool {
+	if ptr, ok := types.Unalias(t).(*types.Pointer); ok {
+		t = ptr.Elem()
+	}
+	named, ok := types.Unalias(t).(*types.Named)
+	return ok && named.Obj().Pkg() != nil && named.Obj().Pkg().Path() == blazorImport && named.Obj().Name() == name
+}
+
+type endpointRef struct {
+	name string
+	obj  types.Object
+	line int
+}
+
+// identPattern matches endpoint method calls in templates: saveEndpoint.HX( or,
+// for an endpoint of an imported package, routes.Save.HX(.
+var identPattern = regexp.MustCompile(`\b(?:([A-Za-z_][A-Za-z0-9_]*)\.)?([A-Za-z_][A-Za-z0-9_]*)\.(HX|URL|Path|Method)\(`)
+
+// endpointReferences finds the package-level blazor.Endpoint variables that the
+// template at path calls, resolved in the scope of its package pkg.
+func endpointReferences(path string, pkg *types.Package) ([]endpointRef, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	imports := make(map[string]*types.Package)
+	for _, imp := range pkg.Imports() {
+		imports[imp.Name()] = imp
+	}
+
+	var refs []endpointRef
+	scanner := bufio.NewScanner(f)
+	for line := 1; scanner.Scan(); line++ {
+		for _, m := range identPattern.FindAllStringSubmatch(scanner.Text(), -1) {
+			scope, name := pkg.Scope(), m[2]
+			if m[1] != "" {
+				imp, ok := imports[m[1]]
+				if !ok {
+					continue
+				}
+				scope, name = imp.Scope(), m[1]+"."+m[2]
+			}
+			obj, ok := scope.Lookup(m[2]).(*types.Var)
+			if ok && isBlazorType(obj.Type(), "Endpoint") {
+				refs = append(refs, endpointRef{name: name, obj: obj, line: line})
+			}
+		}
+	}
+	return refs, scanner.Err()
+}
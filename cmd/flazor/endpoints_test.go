@@ -0,0 +1,96 @@
+package main
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// stubBlazor stands in for the blazor package, so fixtures type-check without
+// the framework's dependencies.
+var stubBlazor = map[string]string{
+	"go.mod": "module github.com/snowmerak/fiber-blazor\n\ngo 1.25\n",
+	"blazor/blazor.go": `package blazor
+
+type Endpoint struct{}
+
+func (Endpoint) HX(params ...any) any { return nil }
+
+type Router struct{}
+
+func (*Router) Get(path string, handler any) Endpoint  { return Endpoint{} }
+func (*Router) Post(path string, handler any) Endpoint { return Endpoint{} }
+`,
+}
+
+func TestCheckEndpoints(t *testing.T) {
+	dir := t.TempDir()
+	writeTree(t, dir, stubBlazor)
+	writeTree(t, dir, map[string]string{
+		// a declares saveEndpoint but never registers it.
+		"app/a/a.go": `package a
+
+import "github.com/snowmerak/fiber-blazor/blazor"
+
+var saveEndpoint blazor.Endpoint
+
+var Save blazor.Endpoint
+`,
+		"app/a/a.templ": "package a\n\ntempl A() {\n\t<button { saveEndpoint.HX() }></button>\n}\n",
+		// b registers a variable of the same name in another package.
+		"app/b/b.go": `package b
+
+import "github.com/snowmerak/fiber-blazor/blazor"
+
+var saveEndpoint blazor.Endpoint
+
+var listEndpoint = new(blazor.Router).Get("/list", nil)
+
+func Register(r *blazor.Router) {
+	saveEndpoint = r.Post("/save", nil)
+}
+`,
+		"app/b/b.templ": "package b\n\ntempl B() {\n\t<button { saveEndpoint.HX() }></button>\n\t<a { listEndpoint.HX() }></a>\n}\n",
+		// c calls Post on something that is not a blazor.Router.
+		"app/c/c.go": `package c
+
+import "github.com/snowmerak/fiber-blazor/blazor"
+
+type fakeRouter struct{}
+
+func (fakeRouter) Post(path string, handler any) blazor.Endpoint { return blazor.Endpoint{} }
+
+var deleteEndpoint blazor.Endpoint
+
+func init() {
+	deleteEndpoint = fakeRouter{}.Post("/delete", nil)
+}
+`,
+		"app/c/c.templ": "package c\n\ntempl C() {\n\t<button { deleteEndpoint.HX() }></button>\n}\n",
+		// main registers a.Save, which a template references through the import.
+		"app/main.go": `package main
+
+import (
+	"github.com/snowmerak/fiber-blazor/app/a"
+	"github.com/snowmerak/fiber-blazor/blazor"
+)
+
+func main() {
+	a.Save = new(blazor.Router).Post("/a", nil)
+}
+`,
+		"app/main.templ": "package main\n\ntempl Main() {\n\t<form { a.Save.HX() }></form>\n}\n",
+	})
+
+	err := checkEndpoints(dir, config{})
+	if err == nil {
+		t.Fatal("Expected unregistered endpoints")
+	}
+	want := []string{
+		filepath.Join(dir, "app", "a", "a.templ") + ":4: endpoint saveEndpoint is used but never registered with a blazor.Router",
+		filepath.Join(dir, "app", "c", "c.templ") + ":4: endpoint deleteEndpoint is used but never registered with a blazor.Router",
+	}
+	if got := strings.Split(err.Error(), "\n")[1:]; strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Errorf("Expected:\n%s\ngot:\n%s", strings.Join(want, "\n"), strings.Join(got, "\n"))
+	}
+}
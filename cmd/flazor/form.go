@@ -0,0 +1,165 @@
+package main
+
+import (
+	"fmt"
+	"go/types"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// formInput is one field of a generated Form<Struct> component.
+type formInput struct {
+	name    string   // Go field name, also the binder and value selector
+	label   string   // from the label tag or the field name
+	input   string   // blazor.Input* constant
+	options []string // enum constants for selects, qualified for the generated file
+	file    bool     // files cannot be pre-filled
+}
+
+// formInputs picks an input for every field that maps onto one form control.
+// Nested structs, slices and maps have no single control and are left out;
+// promoted fields of embedded structs are included in place.
+func (g *genFile) formInputs(fields []fieldInfo, local map[string]bool) []formInput {
+	var inputs []formInput
+	for _, field := range fields {
+		in := formInput{name: field.FieldName, label: field.Tag.get("label")}
+		if in.label == "" {
+			in.label = humanize(field.FieldName)
+		}
+
+		switch field.Kind {
+		case fieldEmbedded:
+			inputs = append(inputs, g.formInputs(g.fields[field.Elem], local)...)
+			continue
+		case fieldFile, fieldFiles:
+			in.input, in.file = "blazor.InputFile", true
+			inputs = append(inputs, in)
+			continue
+		case fieldScalar:
+		default:
+			continue
+		}
+
+		t := types.Unalias(field.Type)
+		if ptr, ok := t.(*types.Pointer); ok {
+			t = types.Unalias(ptr.Elem())
+		}
+		if named, ok := t.(*types.Named); ok && named.Obj().Pkg() != nil &&
+			named.Obj().Pkg().Path() == "time" && named.Obj().Name() == "Time" {
+			in.input = "blazor.InputDateTime"
+			inputs = append(inputs, in)
+			continue
+		}
+		if options := g.enumOptions(t); len(options) > 0 {
+			in.input, in.options = "blazor.InputSelect", options
+			inputs = append(inputs, in)
+			continue
+		}
+
+		switch u := t.Underlying().(type) {
+		case *types.Basic:
+			switch {
+			case u.Info()&types.IsBoolean != 0:
+				in.input = "blazor.InputCheckbox"
+			case u.Info()&types.IsNumeric != 0:
+				in.input = "blazor.InputNumber" // the binder's Constrain adds step="any" for floats
+			case field.Tag.hasRule("email"):
+				in.input = "blazor.InputEmail"
+			default:
+				in.input = "blazor.InputText"
+			}
+		case *types.Slice, *types.Array:
+			continue // several values, no single control
+		default:
+			in.input = "blazor.InputText" // an encoding.TextUnmarshaler
+		}
+		inputs = append(inputs, in)
+	}
+	return inputs
+}
+
+// enumOptions returns the constants declared for a named basic type, in source
+// order. Standard library types such as time.Duration are not treated as enums.
+func (g *genFile) enumOptions(t types.Type) []string {
+	named, ok := t.(*types.Named)
+	if !ok || named.Obj().Pkg() == nil {
+		return nil
+	}
+	if basic, ok := named.Underlying().(*types.Basic); !ok || basic.Info()&types.IsBoolean != 0 {
+		return nil
+	}
+	pkg := named.Obj().Pkg()
+	if first, _, _ := strings.Cut(pkg.Path(), "/"); !strings.Contains(first, ".") && pkg != g.pkg {
+		return nil
+	}
+
+	var consts []*types.Const
+	for _, name := range pkg.Scope().Names() {
+		c, ok := pkg.Scope().Lookup(name).(*types.Const)
+		if !ok || name == "_" || !types.Identical(c.Type(), named) || (pkg != g.pkg && !c.Exported()) {
+			continue
+		}
+		consts = append(consts, c)
+	}
+	sort.Slice(consts, func(i, j int) bool { return consts[i].Pos() < consts[j].Pos() })
+
+	options := make([]string, len(consts))
+	for i, c := range consts {
+		options[i] = c.Name()
+		if qualifier := g.qualifier(pkg); qualifier != "" {
+			options[i] = qualifier + "." + c.Name()
+		}
+	}
+	return options
+}
+
+// humanize turns a field name into a label: CreatedAt -> Created at.
+func humanize(name string) string {
+	words := joinWords(name, " ")
+	r, size := utf8.DecodeRuneInString(words)
+	return string(unicode.ToUpper(r)) + words[size:]
+}
+
+// writeForm emits Form<T>, a component that renders the generated binder as a
+// form through blazor.Form.
+func writeForm(f io.Writer, t string, inputs []formInput) {
+	fmt.Fprintf(f, "// Form%s renders a form with a labeled input for each field of %s.\n", t, t)
+	fmt.Fprintf(f, "// value pre-fills the inputs when it is not nil; opts restyle the markup.\n")
+	fmt.Fprintf(f, "func Form%s(submit *blazor.HXAttr, value *%s, opts ...blazor.FormOption) templ.Component {\n", t, t)
+	fmt.Fprintf(f, "\treturn templ.ComponentFunc(func(ctx context.Context, w io.Writer) error {\n")
+	fmt.Fprintf(f, "\t\tb := GetBindingOf%sFrom(ctx)\n", t)
+	fmt.Fprintf(f, "\t\tinputs := []blazor.FormInput{\n")
+	for _, in := range inputs {
+		fmt.Fprintf(f, "\t\t\t{Name: %q, Label: %s, Type: %s, Field: b.%s", in.name, strconv.Quote(in.label), in.input, in.name)
+		if len(in.options) > 0 {
+			fmt.Fprintf(f, ", Options: []blazor.SelectOption{\n")
+			for _, option := range in.options {
+				fmt.Fprintf(f, "\t\t\t\tblazor.Option(%s),\n", option)
+			}
+			fmt.Fprintf(f, "\t\t\t}")
+		}
+		fmt.Fprintf(f, "},\n")
+	}
+	fmt.Fprintf(f, "\t\t}\n")
+
+	var prefill []string
+	for i, in := range inputs {
+		if !in.file {
+			prefill = append(prefill, fmt.Sprintf("\t\t\tinputs[%d].Value = blazor.FormValue(value.%s)\n", i, in.name))
+		}
+	}
+	if len(prefill) > 0 {
+		fmt.Fprintf(f, "\t\tif value != nil {\n")
+		for _, line := range prefill {
+			fmt.Fprint(f, line)
+		}
+		fmt.Fprintf(f, "\t\t}\n")
+	}
+	fmt.Fprintf(f, "\t\treturn blazor.Form(submit, inputs, opts...).Render(ctx, w)\n")
+	fmt.Fprintf(f, "\t})\n")
+	fmt.Fprintf(f, "}\n\n")
+}
@@ -0,0 +1,83 @@
+package main
+
+import (
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"golang.org/x/tools/go/packages"
+)
+
+// loadPackages loads the packages under root matching patterns (./... when
+// none are given), sorted by import path. generate, check and the endpoint
+// check all discover code through it, so they agree on which packages exist:
+// build tags and module boundaries are honoured and nothing is skipped by name.
+func loadPackages(root string, cfg config, patterns ...string) ([]*packages.Package, error) {
+	absRoot, err := filepath.Abs(root)
+	if err != nil {
+		return nil, err
+	}
+	if len(patterns) == 0 {
+		patterns = []string{"./..."}
+	}
+
+	// Dependencies are type-checked from source rather than export data, which ties
+	// the loader to the toolchain's export format. Only their declarations matter,
+	// so function bodies outside root are dropped to keep that fast. Bodies under
+	// root are kept for the endpoint check, which looks at Router calls in main.
+	loadCfg := &packages.Config{
+		Mode: packages.NeedName | packages.NeedFiles | packages.NeedSyntax | packages.NeedImports | packages.NeedDeps | packages.NeedTypes | packages.NeedTypesInfo,
+		Dir:  root,
+		ParseFile: func(fset *token.FileSet, filename string, src []byte) (*ast.File, error) {
+			f, err := parser.ParseFile(fset, filename, src, parser.ParseComments|parser.SkipObjectResolution)
+			if f != nil && !within(absRoot, filename) {
+				for _, decl := range f.Decls {
+					if fn, ok := decl.(*ast.FuncDecl); ok {
+						fn.Body = nil
+					}
+				}
+			}
+			return f, err
+		},
+	}
+	if len(cfg.BuildTags) > 0 {
+		loadCfg.BuildFlags = []string{"-tags=" + strings.Join(cfg.BuildTags, ",")}
+	}
+	pkgs, err := packages.Load(loadCfg, patterns...)
+	if err != nil {
+		return nil, fmt.Errorf("load packages: %w", err)
+	}
+	sort.Slice(pkgs, func(i, j int) bool { return pkgs[i].PkgPath < pkgs[j].PkgPath })
+	return pkgs, nil
+}
+
+// loadErrors returns the errors that kept pkg from being read. Type errors are
+// left out: they are expected while a package's generated file is stale or missing.
+func loadErrors(pkg *packages.Package) []string {
+	var errs []string
+	for _, e := range pkg.Errors {
+		if e.Kind != packages.TypeError {
+			errs = append(errs, e.Error())
+		}
+	}
+	return errs
+}
+
+// within reports whether path lies inside dir.
+func within(dir, path string) bool {
+	rel, err := filepath.Rel(dir, path)
+	return err == nil && rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator))
+}
+
+// rootPath turns an absolute path from the loader back into one relative to
+// root as the user wrote it, for messages and for matching .templ paths.
+func rootPath(root, absRoot, path string) string {
+	if rel, err := filepath.Rel(absRoot, path); err == nil {
+		return filepath.Join(root, rel)
+	}
+	return path
+}
@@ -1,24 +1,48 @@
 package main
 
 import (
+	"bytes"
 	"context"
 	"crypto/rand"
 	"encoding/hex"
+	"errors"
 	"fmt"
 	"go/ast"
 	"go/format"
-	"go/parser"
 	"go/token"
+	"go/types"
+	"io"
 	"os"
 	"path/filepath"
 	"regexp"
+	"sort"
+	"strconv"
 	"strings"
 
 	"github.com/a-h/templ/cmd/templ/generatecmd"
+	"golang.org/x/tools/go/packages"
 )
 
 func main() {
-	if err := run(); err != nil {
+	var command string
+	if len(os.Args) > 1 {
+		command = os.Args[1]
+	}
+
+	var err error
+	switch command {
+	case "dev":
+		err = runDev(os.Args[2:])
+	case "new":
+		err = runNew(os.Args[2:])
+	case "add":
+		err = runAdd(os.Args[2:])
+	case "check":
+		os.Exit(runCheck(os.Args[2:], os.Stdout, os.Stderr))
+	default:
+		err = run()
+	}
+	if err != nil {
 		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
 		os.Exit(1)
 	}
@@ -33,21 +57,31 @@ func randomString(n int) string {
 }
 
 func run() error {
+	cfg, err := loadConfig(".")
+	if err != nil {
+		return err
+	}
+
 	// 1. Scan for //blazor:bind
-	if err := generateBinders("."); err != nil {
+	if err := generateBinders(".", cfg, nil); err != nil {
 		return fmt.Errorf("generate binders: %w", err)
 	}
 
-	// 2. Generate Agent Skill
+	// 2. Make sure templates only use registered endpoints
+	if err := checkEndpoints(".", cfg); err != nil {
+		return err
+	}
+
+	// 3. Generate Agent Skill
 	if err := generateSkill("."); err != nil {
 		fmt.Fprintf(os.Stderr, "Warning: failed to generate skill: %v\n", err)
 	}
 
-	// 3. Run templ generate
+	// 4. Run templ generate
 	fmt.Println("Running templ generate...")
 	ctx := context.Background()
 	// Pass empty args logic or just run with defaults
-	err := generatecmd.Run(ctx, os.Stdout, os.Stderr, nil)
+	err = generatecmd.Run(ctx, os.Stdout, os.Stderr, nil)
 	if err != nil {
 		return fmt.Errorf("templ generate: %w", err)
 	}
@@ -56,19 +90,10 @@ func run() error {
 }
 
 func generateSkill(root string) error {
-	modData, err := os.ReadFile(filepath.Join(root, "go.mod"))
-	if err != nil {
+	modName := modulePath(root)
+	if modName == "" {
 		return nil // Not a go module root
 	}
-	modLines := strings.Split(string(modData), "\n")
-	var modName string
-	for _, line := range modLines {
-		line = strings.TrimSpace(line)
-		if after, ok := strings.CutPrefix(line, "module "); ok {
-			modName = after
-			break
-		}
-	}
 
 	parts := strings.Split(strings.TrimSpace(modName), "/")
 	shortName := parts[len(parts)-1]
@@ -105,8 +130,25 @@ func generateSkill(root string) error {
 
 	sb.WriteString("## Key Concepts\n\n")
 	sb.WriteString("- **Form Binding**: Look for `//blazor:bind` on structs. These generate randomized tags for security and isolation.\n")
+	sb.WriteString("- **Generated Code**: each package with `//blazor:bind` structs gets one `blazor_gen.go` (Binded structs, binders, converters). Never edit it; rerun `flazor`. Set `buildTags` in `flazor.json` when bound structs live behind build constraints.\n")
 	sb.WriteString("- **Templ Components**: Use `GetBindingOf[StructName]()` to get a binder that helps generate IDs and Names for HTML elements.\n")
-	sb.WriteString("- **HTMX Attributes**: Use `blazor.Post()`, `blazor.Target()`, etc., to build htmx attributes in Go/Templ.\n")
+	sb.WriteString("- **Component Instances**: Use `GetBindingOf[StructName]From(ctx)` inside components and wrap each placement in `blazor.Scoped(scope, component)` so repeated components get distinct IDs and input names. Send the scope back with `.Scope(binder.Scope())` on the htmx builder; the server binds only that instance's values.\n")
+	sb.WriteString("- **HTMX Headers**: Use `blazor.HX(c)` to read htmx request headers and `blazor.HXResponse` (or `blazor.SetResultRenderer` with `blazor.Reply(data)`) to send `HX-Redirect`, `HX-Trigger`, `HX-Retarget` and friends.\n")
+	sb.WriteString("- **Out-of-Band Updates**: Return `blazor.Compose(primary).OOB(binder.ID(\"name\"), component)` from `componentFunc` to update several regions in one response.\n")
+	sb.WriteString("- **Live Components**: `blazor.SSE(db, blazor.SSEConfig{...})` re-renders a component whenever a `ledis` channel receives a message or a tracked key changes; connect an element with `blazor.SSEConnect(url, event)` and set `SSE: true` on the `blazor.Layout`.\n")
+	sb.WriteString("- **WebSocket Components**: register typed handlers with `blazor.HandleWS(hub, name, fn)` and mount `hub.Handler()`; elements use `blazor.WSConnect(url)` and `blazor.WSSend(name)` (set `WS: true` on the `blazor.Layout`), and handlers reply with `conn.Send` or `hub.Broadcast(ctx, group, ...)` (out-of-band swaps by id). Behind a TLS-terminating proxy, set `hub.AllowedOrigins` to the public origins.\n")
+	sb.WriteString("- **Layout**: `blazor.InitLayout(root, blazor.Layout{...})` configures meta tags, assets, the static prefix and `htmx-config`; override a route's title or description with `blazor.SetTitle(c, ...)` and `blazor.SetDescription(c, ...)`.\n")
+	sb.WriteString("- **Security**: `app.Use(blazor.Security(blazor.SecurityConfig{}))` adds a CSP nonce to framework scripts and a CSRF token to every htmx request; `SetRenderer` verifies it, custom handlers call `blazor.VerifyCSRF(c)` and a custom `Layout.Template` spreads `blazor.WithCSRFHeader(ctx, layout.BodyAttrs)` on `<body>`.\n")
+	sb.WriteString("- **Full Pages**: with `app.Use(blazor.UseLayout(layout))` or `blazor.WithLayout(layout)`, `SetRenderer` wraps its fragment in the layout for non-htmx, boosted and history-restore requests.\n")
+	sb.WriteString("- **Stateful Components**: `blazor.NewStateful[S](db, name, blazor.StatefulConfig{...})` with `blazor.SetStatefulRenderer(store, componentFunc, func(req *Binded..., state *S) (*V, error))` keeps per-session, per-instance state in `ledis`; the instance ID is the binder scope.\n")
+	sb.WriteString("- **Components**: types implementing `blazor.Component` (embed `blazor.ComponentBase`) get their `On*` methods mounted as routes by `blazor.NewRegistry(app, cfg).Mount(name, factory)`; target them with `blazor.Call(c.OnClick)` and render with `blazor.RenderComponent(c)`.\n")
+	sb.WriteString("- **Typed Endpoints**: register routes with `blazor.NewRouter(app).Post(path, handler)` and keep the returned `blazor.Endpoint` in a package variable; templates call `endpoint.HX(params...)` instead of `blazor.Post(url)`. `flazor` fails if a template uses an endpoint that is never registered.\n")
+	sb.WriteString("- **Nested Binders**: fields typed as another `//blazor:bind` struct (`X`, `*X`) get a nested `BindingOfX`, slices (`[]X`) get `blazor.List` with `.At(i)` and `map[string]T` fields get `blazor.Map` with `.Key(k)`; names use dot notation (`rows_xxxx.0.name_yyyy`). Untagged embedded bindable structs are promoted; unsupported fields stop `flazor` with `file:line` errors.\n")
+	sb.WriteString("- **Stable Form Names**: `flazor.json` selects the suffix strategy (`random`, `deterministic` with a `salt`, or `rotate` with `FLAZOR_ROTATION`); generated headers record it as `// flazor: suffix=...`. Only `form`, `query`, `header` and `cookie` tags (or the `tags` list in `flazor.json`) get the suffix; `,omitempty` options and other tags are kept verbatim.\n")
+	sb.WriteString("- **File Uploads**: `*multipart.FileHeader` and `[]*multipart.FileHeader` fields get file inputs (`type=\"file\"`, `accept` from the `mime=` rule, `multiple` for slices). Validate with `maxsize=2MB`, `mime=image/png|image/*|.pdf` and `maxfiles=N`, cap the request with `blazor.MaxUploadSize(n)`, and send the form with `.Encoding(blazor.EncodingMultipart)`; `.Progress(\"#bar\")` shows upload progress when the `blazor.Layout` sets `Uploads: true`.\n")
+	sb.WriteString("- **Generated Forms**: add `//blazor:form` next to `//blazor:bind` to get `Form[StructName](submit *blazor.HXAttr, value *[StructName], opts...)`, a labeled input per field (`label:\"...\"` tag, number/checkbox/datetime-local/select by Go type). Restyle with `blazor.WithInput(fn)` (fall back to `blazor.DefaultInput(in)`), `blazor.WithSubmit(component)` and `blazor.WithFormAttrs(attrs)`. Call `blazor.RegisterTimeParser()` once in `main` so datetime-local values bind to `time.Time`.\n")
+	sb.WriteString("- **Client-Side Validation**: generated binder fields carry their `validate` rules, so `field.Attrs()` also renders `required`, `min`/`max`, `minlength`/`maxlength`, `pattern` (anchored `^...$` regexes only), `step` and `inputmode`. The server still checks every rule in `SetRenderer`.\n")
+	sb.WriteString("- **HTMX Attributes**: Use `blazor.Post()`, `blazor.Target()`, etc., to build htmx attributes in Go/Templ. Spread them with `.Attrs(ctx)...` in templates; invalid values become a 500 instead of a panic. `Build()` returns the valid attributes, `Err()` reports invalid ones and `MustBuild()` panics.\n")
 	sb.WriteString("- **Utility-First CSS**: **Tailwind CSS** is integrated. You can use Tailwind classes directly in your `.templ` files for styling.\n\n")
 
 	sb.WriteString("## Fiber v3 Integration\n\n")
@@ -115,16 +157,19 @@ func generateSkill(root string) error {
 	sb.WriteString("- **`blazor.Static(app, prefix)`**: Configures static file serving for embedded files (e.g., `htmx.js`, `tailwindcss.js`).\n")
 	sb.WriteString("- **`blazor.SetRenderer(componentFunc, transformFunc)`**: Handles HTMX requests. \n")
 	sb.WriteString("  - `transformFunc` takes the randomized request struct (`Binded[StructName]`) and converts it to data.\n")
-	sb.WriteString("  - `componentFunc` renders the data into a Templ component.\n\n")
+	sb.WriteString("  - `componentFunc` renders the data into a Templ component.\n")
+	sb.WriteString("  - `blazor.SetConvertedRenderer(componentFunc, (*Binded[StructName]).To[StructName], transform)` hands the transform your original `*[StructName]` instead; `From[StructName](v)` fills a `Binded` struct back.\n")
+	sb.WriteString("  - `validate:\"...\"` tags (required, min, max, len, minlen, maxlen, email, regex and `blazor.RegisterValidator` rules) are checked before the transform. Pass `blazor.OnInvalid(...)` to re-render a component whose `GetBindingOf[StructName]From(ctx)` fields carry the messages.\n\n")
 
 	sb.WriteString("## Common Tasks\n\n")
-	sb.WriteString("1. **Add a new bindable struct**: Add `//blazor:bind` above your struct definition.\n")
-	sb.WriteString("2. **Generate code**: Run `flazor` to sync everything.\n")
+	sb.WriteString("1. **Add a new bindable struct**: Add `//blazor:bind` above your struct definition, or run `flazor add component Name` / `flazor add page Name` to scaffold one with its template and handler.\n")
+	sb.WriteString("2. **Generate code**: Run `flazor` to sync everything, or `flazor dev -app ./path/to/main` to regenerate, restart and reload the browser on every change.\n")
 	sb.WriteString("3. **Create a template**: Use the binder in your `.templ` file to bind inputs.\n")
 	sb.WriteString("4. **Handle requests**: Use `blazor.SetRenderer` in your Fiber app to process the form data.\n")
 	sb.WriteString("5. **Serve Static Files**: Use `blazor.Static(app, \"/statics\")` in your `main.go` to serve embedded files.\n")
+	sb.WriteString("6. **Check templates**: Run `flazor check` (or `flazor check -json` in CI) to find binder fields no template spreads, `Target`/`Include` selectors that match no element, and IDs a component renders twice. It exits 1 on findings and 2 when the check itself fails.\n")
 
-	err = os.WriteFile(skillPath, []byte(sb.String()), 0644)
+	err := os.WriteFile(skillPath, []byte(sb.String()), 0644)
 	if err != nil {
 		return err
 	}
@@ -133,182 +178,772 @@ func generateSkill(root string) error {
 	return nil
 }
 
-func generateBinders(root string) error {
-	fset := token.NewFileSet()
-	return filepath.Walk(root, func(path string, info os.FileInfo, err error) error {
+// genFileName is the file flazor writes into every package with //blazor:bind structs.
+const genFileName = "blazor_gen.go"
+
+// genHeader starts every generated file. Files carrying it belong to flazor and
+// are replaced or removed when their package changes.
+const genHeader = "// Code generated by blazor-gen. DO NOT EDIT."
+
+// Packages the generated code refers to.
+const (
+	blazorImport = "github.com/snowmerak/fiber-blazor/blazor"
+	templImport  = "github.com/a-h/templ"
+)
+
+// generateBinders writes one blazor_gen.go for every package under root with
+// //blazor:bind structs. Packages are loaded with go/packages, so build tags and
+// module boundaries are honoured and field types are resolved by the type checker.
+// When dirs is non-nil only the packages in those directories are regenerated.
+func generateBinders(root string, cfg config, dirs map[string]bool) error {
+	absRoot, err := filepath.Abs(root)
+	if err != nil {
+		return err
+	}
+
+	patterns := []string{"./..."}
+	if dirs != nil {
+		patterns = nil
+		for dir := range dirs {
+			if info, err := os.Stat(filepath.Join(root, dir)); err == nil && info.IsDir() {
+				patterns = append(patterns, "./"+filepath.ToSlash(dir))
+			}
+		}
+		if len(patterns) == 0 {
+			return nil
+		}
+		sort.Strings(patterns)
+	}
+
+	pkgs, err := loadPackages(root, cfg, patterns...)
+	if err != nil {
+		return err
+	}
+
+	// Unsupported declarations are collected so every problem is reported at once.
+	var problems []string
+	var pending []*genFile
+	for _, pkg := range pkgs {
+		if len(pkg.GoFiles) == 0 {
+			continue // e.g. a directory holding only templates or assets
+		}
+		problems = append(problems, loadErrors(pkg)...)
+
+		dir, err := filepath.Rel(absRoot, pkg.Dir)
 		if err != nil {
 			return err
 		}
-		if info.IsDir() {
-			if info.Name() == "vendor" || info.Name() == ".git" || info.Name() == "blazor" || info.Name() == "statics" {
-				return filepath.SkipDir
+		g, pkgProblems := collectBindings(pkg, cfg.Tags, func(path string) string {
+			return rootPath(root, absRoot, path)
+		})
+		g.dir = dir
+		problems = append(problems, pkgProblems...)
+		pending = append(pending, g)
+	}
+	if len(problems) > 0 {
+		return fmt.Errorf("unsupported bind declarations:\n%s", strings.Join(problems, "\n"))
+	}
+
+	suffixes := suffixer{cfg: cfg.Suffix, module: modulePath(root)}
+	for _, g := range pending {
+		if len(g.types) > 0 {
+			if err := writeGenFile(root, g, suffixes, cfg.Tags); err != nil {
+				return err
 			}
-			return nil
 		}
-		if !strings.HasSuffix(path, ".go") || strings.HasSuffix(path, "_gen.go") || path == "main.go" {
-			return nil
+		for _, path := range g.stale {
+			if err := os.Remove(path); err != nil && !errors.Is(err, os.ErrNotExist) {
+				return err
+			}
+			fmt.Printf("Removed %s\n", rootPath(root, absRoot, path))
 		}
+	}
+	return nil
+}
 
-		f, err := parser.ParseFile(fset, path, nil, parser.ParseComments)
-		if err != nil {
-			return nil // Skip files that don't parse
+// collectBindings reads the //blazor:bind structs of one package. Problems are
+// reported with file names passed through relPath.
+func collectBindings(pkg *packages.Package, tags []string, relPath func(string) string) (*genFile, []string) {
+	g := &genFile{
+		pkg:    pkg.Types,
+		fields: make(map[string][]fieldInfo),
+		imports: map[string]importName{
+			"context":    {name: "context", pkgName: "context"},
+			blazorImport: {name: "blazor", pkgName: "blazor"},
+		},
+	}
+	var problems []string
+	report := func(pos token.Pos, format string, args ...any) {
+		position := pkg.Fset.Position(pos)
+		position.Filename = relPath(position.Filename)
+		problems = append(problems, fmt.Sprintf("%s: %s", position, fmt.Sprintf(format, args...)))
+	}
+
+	// First pass: a struct can embed or nest a bindable struct declared in
+	// another file of the package.
+	var specs []bindSpec
+	local := make(map[string]bool)
+	target := filepath.Join(pkg.Dir, genFileName)
+	hasTarget := false
+	for _, f := range pkg.Syntax {
+		if isGenerated(f) {
+			path := pkg.Fset.File(f.Pos()).Name()
+			if path == target {
+				hasTarget = true
+			} else {
+				g.stale = append(g.stale, path) // a per-file _gen.go from an older flazor
+			}
+			continue
+		}
+		for _, typeSpec := range bindSpecs(f) {
+			specs = append(specs, typeSpec)
+			if _, ok := typeSpec.Type.(*ast.StructType); ok {
+				local[typeSpec.Name.Name] = true
+			}
 		}
+	}
 
-		var typesToGen []string
-		var structFields = make(map[string][]fieldInfo)
+	for _, typeSpec := range specs {
+		typeName := typeSpec.Name.Name
+		structType, ok := typeSpec.Type.(*ast.StructType)
+		if !ok {
+			report(typeSpec.Pos(), "%s: //blazor:bind only applies to struct types", typeName)
+			continue
+		}
+		if typeSpec.TypeParams != nil {
+			report(typeSpec.Pos(), "%s: generic structs cannot be bound", typeName)
+			continue
+		}
+		g.types = append(g.types, typeName)
+		if typeSpec.form {
+			g.forms = append(g.forms, typeName)
+		}
 
-		for _, decl := range f.Decls {
-			genDecl, ok := decl.(*ast.GenDecl)
-			if !ok || genDecl.Tok != token.TYPE {
+		var fields []fieldInfo
+		for _, field := range structType.Fields.List {
+			tag, err := parseTag(field.Tag)
+			if err != nil {
+				report(field.Pos(), "%s: %v", typeName, err)
 				continue
 			}
-
-			shouldGen := false
-			if genDecl.Doc != nil {
-				for _, comment := range genDecl.Doc.List {
-					if strings.Contains(comment.Text, "//blazor:bind") {
-						shouldGen = true
-						break
-					}
-				}
+			t := pkg.TypesInfo.TypeOf(field.Type)
+			if t == nil || strings.Contains(types.TypeString(t, nil), "invalid type") {
+				report(field.Pos(), "%s: cannot resolve the type %s; fix the package's build errors first", typeName, types.ExprString(field.Type))
+				continue
 			}
 
-			if !shouldGen {
+			if tag.skipped(tags) {
 				continue
 			}
 
-			for _, spec := range genDecl.Specs {
-				typeSpec, ok := spec.(*ast.TypeSpec)
-				if !ok {
+			if len(field.Names) == 0 {
+				info, err := g.embeddedField(t, tag, tags, local)
+				if err != nil {
+					report(field.Pos(), "%s: embedded %s: %v", typeName, types.ExprString(field.Type), err)
 					continue
 				}
-				structType, ok := typeSpec.Type.(*ast.StructType)
-				if !ok {
+				fields = append(fields, info)
+				continue
+			}
+
+			kind, elem, err := g.classifyField(t, local)
+			if err != nil {
+				report(field.Pos(), "%s.%s: %v", typeName, field.Names[0].Name, err)
+				continue
+			}
+			// Nested bindable structs are decoded into their Binded counterparts
+			// so the randomized tags line up with the nested binder names.
+			fieldType := g.typeString(t, local)
+
+			// A, B int declares one field per name. An explicit name in the shared
+			// tag would bind all of them to the same form value.
+			var names []string
+			for _, name := range field.Names {
+				if name.Name != "_" {
+					names = append(names, name.Name)
+				}
+			}
+			if pair, ok := tag.explicit(tags); ok && len(names) > 1 {
+				report(field.Pos(), "%s.%s: fields declared together share the name in %s:%q; declare them separately", typeName, strings.Join(names, ", "), pair.key, pair.value)
+				continue
+			}
+			for _, name := range field.Names {
+				if name.Name == "_" {
 					continue
 				}
+				fields = append(fields, fieldInfo{
+					FieldName: name.Name,
+					BindName:  tag.name(name.Name, tags),
+					FieldType: fieldType,
+					Tag:       tag,
+					Kind:      kind,
+					Elem:      elem,
+					Type:      t,
+				})
+			}
+		}
+		g.fields[typeName] = fields
+	}
 
-				typeName := typeSpec.Name.Name
-				typesToGen = append(typesToGen, typeName)
-
-				var fields []fieldInfo
-				for _, field := range structType.Fields.List {
-					if len(field.Names) == 0 {
-						continue
-					}
-					fieldName := field.Names[0].Name
-					tag := ""
-					if field.Tag != nil {
-						tag = field.Tag.Value
-					}
-
-					// Get field type string
-					var typeBuf strings.Builder
-					format.Node(&typeBuf, fset, field.Type)
-					fieldType := typeBuf.String()
-
-					// Basic tag parsing for 'field' or 'form'
-					bindName := strings.ToLower(fieldName)
-					if strings.Contains(tag, `form:"`) {
-						parts := strings.Split(tag, `form:"`)
-						if len(parts) > 1 {
-							bindName = strings.Split(parts[1], `"`)[0]
-						}
-					}
-
-					fields = append(fields, fieldInfo{
-						FieldName:   fieldName,
-						BindName:    bindName,
-						FieldType:   fieldType,
-						OriginalTag: tag,
-					})
-				}
-				structFields[typeName] = fields
+	if len(g.forms) > 0 {
+		g.imports["io"] = importName{name: "io", pkgName: "io"}
+		g.imports[templImport] = importName{name: "templ", pkgName: "templ"}
+		g.inputs = make(map[string][]formInput)
+		for _, t := range g.forms {
+			g.inputs[t] = g.formInputs(g.fields[t], local)
+		}
+	}
+
+	if len(g.types) == 0 && hasTarget {
+		g.stale = append(g.stale, target) // the package no longer binds anything
+	}
+	return g, problems
+}
+
+// isGenerated reports whether f was written by flazor.
+func isGenerated(f *ast.File) bool {
+	return len(f.Comments) > 0 && f.Comments[0].Pos() < f.Package && f.Comments[0].List[0].Text == genHeader
+}
+
+// bindedName is the field's name in the Binded struct; embedded fields take the Binded type's name.
+func (field fieldInfo) bindedName() string {
+	if field.Embedded {
+		return "Binded" + field.Elem
+	}
+	return field.FieldName
+}
+
+// constrain returns the Constrain call that carries the field's validate rules
+// into its HTML attributes, or "" when it has none.
+func (field fieldInfo) constrain() string {
+	rules := field.Tag.get("validate")
+	if rules == "" || rules == "-" {
+		return ""
+	}
+	kind := "blazor.ValueOther"
+	if field.Kind == fieldScalar {
+		t := types.Unalias(field.Type)
+		if ptr, ok := t.(*types.Pointer); ok {
+			t = types.Unalias(ptr.Elem())
+		}
+		if basic, ok := t.Underlying().(*types.Basic); ok {
+			switch {
+			case basic.Info()&types.IsInteger != 0:
+				kind = "blazor.ValueInt"
+			case basic.Info()&types.IsFloat != 0:
+				kind = "blazor.ValueFloat"
+			case basic.Info()&types.IsString != 0:
+				kind = "blazor.ValueString"
 			}
 		}
+	}
+	return fmt.Sprintf(".Constrain(%s, %s)", kind, strconv.Quote(rules))
+}
 
-		if len(typesToGen) > 0 {
-			if err := writeGenFile(path, f.Name.Name, typesToGen, structFields); err != nil {
-				return err
+// genFile is the generated file of one package.
+type genFile struct {
+	dir     string // package directory, relative to the project root
+	pkg     *types.Package
+	types   []string
+	forms   []string // types marked //blazor:form
+	fields  map[string][]fieldInfo
+	inputs  map[string][]formInput // form inputs by type
+	imports map[string]importName  // by import path
+	stale   []string               // generated files to remove
+}
+
+type importName struct {
+	name    string // identifier used in the generated file
+	pkgName string // the package's own name
+}
+
+// qualifier names packages in generated types and records their imports.
+// A name already taken in the file gets a numbered alias.
+func (g *genFile) qualifier(p *types.Package) string {
+	if p == g.pkg {
+		return ""
+	}
+	if imp, ok := g.imports[p.Path()]; ok {
+		return imp.name
+	}
+	taken := func(name string) bool {
+		if g.pkg.Scope().Lookup(name) != nil {
+			return true
+		}
+		for _, imp := range g.imports {
+			if imp.name == name {
+				return true
+			}
+		}
+		return false
+	}
+	name := p.Name()
+	for i := 2; taken(name); i++ {
+		name = fmt.Sprintf("%s%d", p.Name(), i)
+	}
+	g.imports[p.Path()] = importName{name: name, pkgName: p.Name()}
+	return name
+}
+
+// bindable returns the name of t when it is a //blazor:bind struct of this package.
+func (g *genFile) bindable(t types.Type, local map[string]bool) string {
+	named, ok := types.Unalias(t).(*types.Named)
+	if !ok || named.Obj().Pkg() != g.pkg || !local[named.Obj().Name()] {
+		return ""
+	}
+	return named.Obj().Name()
+}
+
+// typeString renders t for the generated file, replacing the package's bindable
+// structs with their Binded counterparts. Aliases are resolved so an alias of a
+// bindable type is replaced too.
+func (g *genFile) typeString(t types.Type, local map[string]bool) string {
+	switch t := types.Unalias(t).(type) {
+	case *types.Pointer:
+		return "*" + g.typeString(t.Elem(), local)
+	case *types.Slice:
+		return "[]" + g.typeString(t.Elem(), local)
+	case *types.Array:
+		return fmt.Sprintf("[%d]%s", t.Len(), g.typeString(t.Elem(), local))
+	case *types.Map:
+		return "map[" + g.typeString(t.Key(), local) + "]" + g.typeString(t.Elem(), local)
+	case *types.Named:
+		if name := g.bindable(t, local); name != "" {
+			return "Binded" + name
+		}
+		return types.TypeString(t, g.qualifier)
+	default:
+		return types.TypeString(t, g.qualifier)
+	}
+}
+
+// embeddedField describes an embedded struct. Without a form tag its fields are
+// promoted like Fiber's binder does; with one it is bound as a nested struct.
+func (g *genFile) embeddedField(t types.Type, tag structTag, tags []string, local map[string]bool) (fieldInfo, error) {
+	base := types.Unalias(t)
+	ptr, isPointer := base.(*types.Pointer)
+	if isPointer {
+		base = ptr.Elem()
+	}
+	name := g.bindable(base, local)
+	if name == "" {
+		return fieldInfo{}, fmt.Errorf("only //blazor:bind structs of the same package can be embedded")
+	}
+
+	info := fieldInfo{
+		FieldName: name,
+		FieldType: g.typeString(t, local),
+		Tag:       tag,
+		Kind:      fieldEmbedded,
+		Elem:      name,
+		Embedded:  true,
+	}
+	if tag.has(tags) {
+		info.Kind = fieldNested
+		info.BindName = tag.name(name, tags)
+		return info, nil
+	}
+	if isPointer {
+		// Fiber's binder does not allocate embedded pointers for promoted fields.
+		return fieldInfo{}, fmt.Errorf("embed %s by value or give it a form tag", name)
+	}
+	return info, nil
+}
+
+// hasDirective reports whether doc contains the comment directive, e.g. //blazor:bind.
+func hasDirective(doc *ast.CommentGroup, directive string) bool {
+	if doc == nil {
+		return false
+	}
+	for _, comment := range doc.List {
+		if strings.Contains(comment.Text, directive) {
+			return true
+		}
+	}
+	return false
+}
+
+// bindSpec is a bindable type spec. form records the form directive, which
+// asks for a generated Form component as well.
+type bindSpec struct {
+	*ast.TypeSpec
+	form bool
+}
+
+// bindSpecs returns the type specs marked with //blazor:bind.
+func bindSpecs(f *ast.File) []bindSpec {
+	var specs []bindSpec
+	for _, decl := range f.Decls {
+		genDecl, ok := decl.(*ast.GenDecl)
+		if !ok || genDecl.Tok != token.TYPE {
+			continue
+		}
+
+		for _, spec := range genDecl.Specs {
+			typeSpec := spec.(*ast.TypeSpec)
+			// Directives may sit on the type group or, inside a group, on the spec itself.
+			has := func(directive string) bool {
+				return hasDirective(genDecl.Doc, directive) || hasDirective(typeSpec.Doc, directive)
 			}
+			if has("//blazor:bind") {
+				specs = append(specs, bindSpec{TypeSpec: typeSpec, form: has("//blazor:form")})
+			}
+		}
+	}
+	return specs
+}
+
+type fieldKind int
+
+const (
+	fieldScalar   fieldKind = iota
+	fieldNested             // X or *X where X is a //blazor:bind struct
+	fieldList               // []X or []*X where X is a //blazor:bind struct
+	fieldMap                // map[string]T where T is a string, bool or number
+	fieldEmbedded           // untagged embedded //blazor:bind struct, promoted into the parent
+	fieldFile               // *multipart.FileHeader
+	fieldFiles              // []*multipart.FileHeader
+)
+
+// isFileHeader reports whether t is mime/multipart.FileHeader, the type Fiber's
+// binder decodes uploaded files into.
+func isFileHeader(t types.Type) bool {
+	named, ok := types.Unalias(t).(*types.Named)
+	return ok && named.Obj().Pkg() != nil && named.Obj().Pkg().Path() == "mime/multipart" && named.Obj().Name() == "FileHeader"
+}
+
+// textUnmarshaler is encoding.TextUnmarshaler, which Fiber's binder uses to
+// decode a single form value into a struct such as time.Time.
+var textUnmarshaler = types.NewInterfaceType([]*types.Func{
+	types.NewFunc(token.NoPos, nil, "UnmarshalText", types.NewSignatureType(nil, nil, nil,
+		types.NewTuple(types.NewVar(token.NoPos, nil, "text", types.NewSlice(types.Typ[types.Byte]))),
+		types.NewTuple(types.NewVar(token.NoPos, nil, "", types.Universe.Lookup("error").Type())),
+		false)),
+}, nil).Complete()
+
+// classifyField decides how a field is bound and, for nested and list fields,
+// returns the name of the bindable struct it refers to.
+func (g *genFile) classifyField(t types.Type, local map[string]bool) (fieldKind, string, error) {
+	deref := func(t types.Type) types.Type {
+		if ptr, ok := types.Unalias(t).(*types.Pointer); ok {
+			return types.Unalias(ptr.Elem())
+		}
+		return types.Unalias(t)
+	}
+
+	// base is the type a single form value is decoded into.
+	base := deref(t)
+	if name := g.bindable(base, local); name != "" {
+		return fieldNested, name, nil
+	}
+	// Fiber's binder only fills these two shapes from the multipart form.
+	switch u := types.Unalias(t).(type) {
+	case *types.Pointer:
+		if isFileHeader(u.Elem()) {
+			return fieldFile, "", nil
+		}
+	case *types.Slice:
+		if ptr, ok := types.Unalias(u.Elem()).(*types.Pointer); ok && isFileHeader(ptr.Elem()) {
+			return fieldFiles, "", nil
 		}
+	}
+	if isFileHeader(base) || isFileHeader(elemOf(t)) {
+		return 0, "", fmt.Errorf("uploaded files must be *multipart.FileHeader or []*multipart.FileHeader")
+	}
 
+	switch u := types.Unalias(t).(type) {
+	case *types.Slice:
+		base = deref(u.Elem())
+		if name := g.bindable(base, local); name != "" {
+			return fieldList, name, nil
+		}
+	case *types.Array:
+		base = deref(u.Elem())
+		if g.bindable(base, local) != "" {
+			return 0, "", fmt.Errorf("arrays of bindable structs are not supported, use a slice")
+		}
+	case *types.Map:
+		if name := g.bindable(deref(u.Elem()), local); name != "" {
+			return 0, "", fmt.Errorf("maps of bindable structs are not supported, use a slice of %s", name)
+		}
+		if key, ok := u.Key().Underlying().(*types.Basic); !ok || key.Info()&types.IsString == 0 {
+			return 0, "", fmt.Errorf("map keys must be strings")
+		}
+		// blazor decodes each entry itself and only knows these kinds.
+		elem, ok := u.Elem().Underlying().(*types.Basic)
+		if !ok || elem.Info()&(types.IsBoolean|types.IsInteger|types.IsFloat|types.IsString) == 0 || elem.Kind() == types.Uintptr {
+			return 0, "", fmt.Errorf("map values must be strings, bools or numbers, got %s", types.TypeString(u.Elem(), (*types.Package).Name))
+		}
+		return fieldMap, "", nil
+	}
+
+	switch base.Underlying().(type) {
+	case *types.Struct:
+		if _, named := base.(*types.Named); !named {
+			return 0, "", fmt.Errorf("inline struct types are not supported, declare a //blazor:bind struct")
+		}
+		if !types.Implements(base, textUnmarshaler) && !types.Implements(types.NewPointer(base), textUnmarshaler) {
+			return 0, "", fmt.Errorf("%s is neither a //blazor:bind struct of this package nor an encoding.TextUnmarshaler",
+				types.TypeString(base, (*types.Package).Name))
+		}
+	case *types.Signature:
+		return 0, "", fmt.Errorf("func fields cannot be bound from a form")
+	case *types.Chan:
+		return 0, "", fmt.Errorf("channel fields cannot be bound from a form")
+	case *types.Interface:
+		return 0, "", fmt.Errorf("interface fields cannot be bound from a form")
+	}
+	return fieldScalar, "", nil
+}
+
+// elemOf returns the element type of a slice or array, dereferenced, or nil.
+func elemOf(t types.Type) types.Type {
+	var elem types.Type
+	switch u := types.Unalias(t).(type) {
+	case *types.Slice:
+		elem = u.Elem()
+	case *types.Array:
+		elem = u.Elem()
+	default:
 		return nil
-	})
+	}
+	if ptr, ok := types.Unalias(elem).(*types.Pointer); ok {
+		return ptr.Elem()
+	}
+	return elem
 }
 
 type fieldInfo struct {
-	FieldName   string
-	BindName    string
-	FieldType   string
-	OriginalTag string
+	FieldName string
+	BindName  string
+	FieldType string
+	Tag       structTag
+	Kind      fieldKind
+	Elem      string
+	Embedded  bool
+	Type      types.Type // nil for embedded structs
 }
 
-func writeGenFile(srcPath, pkgName string, types []string, fields map[string][]fieldInfo) error {
-	dir := filepath.Dir(srcPath)
-	genPath := filepath.Join(dir, strings.TrimSuffix(filepath.Base(srcPath), ".go")+"_gen.go")
-
-	f, err := os.Create(genPath)
-	if err != nil {
-		return err
-	}
-	defer f.Close()
+func writeGenFile(root string, g *genFile, suffixes suffixer, tags []string) error {
+	// The package is identified relative to the module root so deterministic
+	// suffixes don't depend on where the project is checked out.
+	pkgPath := filepath.ToSlash(g.dir)
+	fields := g.fields
 
-	fmt.Fprintf(f, "// Code generated by blazor-gen. DO NOT EDIT.\n")
-	fmt.Fprintf(f, "package %s\n\n", pkgName)
-	fmt.Fprintf(f, "import \"github.com/snowmerak/fiber-blazor/blazor\"\n\n")
+	// The file is assembled in memory and gofmt'ed before it is written, so
+	// aligned keys and tags need no bookkeeping here.
+	genPath := filepath.Join(root, g.dir, genFileName)
+	f := new(bytes.Buffer)
 
-	tagRegex := regexp.MustCompile(`(\w+):"([^"]*)"`)
+	fmt.Fprintf(f, "%s\n", genHeader)
+	fmt.Fprint(f, suffixes.header())
+	fmt.Fprintf(f, "\npackage %s\n\n", g.pkg.Name())
+	writeImports(f, g.imports)
 
-	for _, t := range types {
-		structSuffix := randomString(4)
+	for _, t := range g.types {
+		structSuffix := suffixes.suffix(pkgPath, t)
 
 		fmt.Fprintf(f, "type Binded%s struct {\n", t)
 		for _, field := range fields[t] {
-			newTag := field.OriginalTag
-			if newTag != "" {
-				// Remove the backticks if present to make regex matching easier, then put them back
-				isBackticked := strings.HasPrefix(newTag, "`") && strings.HasSuffix(newTag, "`")
-				tagContent := newTag
-				if isBackticked {
-					tagContent = newTag[1 : len(newTag)-1]
-				}
-
-				newTag = tagRegex.ReplaceAllString(tagContent, `${1}:"${2}_`+structSuffix+`"`)
-
-				if isBackticked {
-					newTag = "`" + newTag + "`"
-				}
+			// Promoted embedded structs must stay untagged or they would become nested.
+			newTag := field.Tag.rewrite(field.FieldName, tags, structSuffix, field.Kind != fieldEmbedded).String()
+			if field.Embedded {
+				fmt.Fprintf(f, "\t%s %s\n", field.FieldType, newTag)
+			} else {
+				fmt.Fprintf(f, "\t%s %s %s\n", field.FieldName, field.FieldType, newTag)
 			}
-			fmt.Fprintf(f, "\t%s %s %s\n", field.FieldName, field.FieldType, newTag)
 		}
 		fmt.Fprintf(f, "}\n\n")
 
 		constPrefix := "bind_" + t + "_"
 		fmt.Fprintf(f, "const (\n")
 		for _, field := range fields[t] {
+			if field.Kind == fieldEmbedded {
+				continue // promoted fields use the embedded struct's own names
+			}
 			fmt.Fprintf(f, "\t%s%s = \"%s_%s\"\n", constPrefix, field.FieldName, field.BindName, structSuffix)
 		}
 		fmt.Fprintf(f, ")\n\n")
 
+		writeConverters(f, t, fields[t])
+
 		binderName := "BindingOf" + t
 		fmt.Fprintf(f, "type %s struct {\n", binderName)
 		fmt.Fprintf(f, "\t*blazor.Binding\n")
 		for _, field := range fields[t] {
-			fmt.Fprintf(f, "\t%s blazor.Field\n", field.FieldName)
+			switch field.Kind {
+			case fieldNested:
+				fmt.Fprintf(f, "\t%s BindingOf%s\n", field.FieldName, field.Elem)
+			case fieldList:
+				fmt.Fprintf(f, "\t%s blazor.List[BindingOf%s]\n", field.FieldName, field.Elem)
+			case fieldMap:
+				fmt.Fprintf(f, "\t%s blazor.Map\n", field.FieldName)
+			case fieldEmbedded:
+				fmt.Fprintf(f, "\tBindingOf%s\n", field.Elem)
+			default:
+				fmt.Fprintf(f, "\t%s blazor.Field\n", field.FieldName)
+			}
 		}
 		fmt.Fprintf(f, "}\n\n")
 
+		// Get* renders without an instance scope; Get*From picks the scope up from
+		// the templ render context so repeated components get distinct IDs.
 		fmt.Fprintf(f, "func Get%s() %s {\n", binderName, binderName)
-		fmt.Fprintf(f, "\tb := blazor.NewBinding()\n")
+		fmt.Fprintf(f, "\treturn new%s(blazor.NewBinding(\"\"))\n", binderName)
+		fmt.Fprintf(f, "}\n\n")
+
+		fmt.Fprintf(f, "func Get%sFrom(ctx context.Context) %s {\n", binderName, binderName)
+		fmt.Fprintf(f, "\treturn new%s(blazor.BindingFrom(ctx))\n", binderName)
+		fmt.Fprintf(f, "}\n\n")
+
+		fmt.Fprintf(f, "func new%s(b *blazor.Binding) %s {\n", binderName, binderName)
 		fmt.Fprintf(f, "\treturn %s{\n", binderName)
 		fmt.Fprintf(f, "\t\tBinding: b,\n")
 		for _, field := range fields[t] {
-			fmt.Fprintf(f, "\t\t%s: b.Field(%s%s),\n", field.FieldName, constPrefix, field.FieldName)
+			constName := constPrefix + field.FieldName
+			switch field.Kind {
+			case fieldNested:
+				fmt.Fprintf(f, "\t\t%s: newBindingOf%s(b.Nested(%s)),\n", field.FieldName, field.Elem, constName)
+			case fieldList:
+				fmt.Fprintf(f, "\t\t%s: blazor.NewList(b, %s, newBindingOf%s),\n", field.FieldName, constName, field.Elem)
+			case fieldMap:
+				fmt.Fprintf(f, "\t\t%s: blazor.NewMap(b, %s),\n", field.FieldName, constName)
+			case fieldEmbedded:
+				fmt.Fprintf(f, "\t\tBindingOf%s: newBindingOf%s(b),\n", field.Elem, field.Elem)
+			case fieldFile, fieldFiles:
+				method := "File"
+				if field.Kind == fieldFiles {
+					method = "Files"
+				}
+				fmt.Fprintf(f, "\t\t%s: b.%s(%s)%s,\n", field.FieldName, method, strings.Join(append([]string{constName}, field.Tag.accept()...), ", "), field.constrain())
+			default:
+				fmt.Fprintf(f, "\t\t%s: b.Field(%s)%s,\n", field.FieldName, constName, field.constrain())
+			}
 		}
 		fmt.Fprintf(f, "\t}\n")
 		fmt.Fprintf(f, "}\n\n")
+
+		if inputs, ok := g.inputs[t]; ok {
+			writeForm(f, t, inputs)
+		}
 	}
 
+	src, err := format.Source(f.Bytes())
+	if err != nil {
+		return fmt.Errorf("format %s: %w", genPath, err)
+	}
+	if err := os.WriteFile(genPath, src, 0o644); err != nil {
+		return err
+	}
 	fmt.Printf("Generated %s\n", genPath)
 	return nil
 }
+
+// writeImports writes the import block, standard library first.
+func writeImports(f io.Writer, imports map[string]importName) {
+	var std, other []string
+	for path := range imports {
+		if first, _, _ := strings.Cut(path, "/"); strings.Contains(first, ".") {
+			other = append(other, path)
+		} else {
+			std = append(std, path)
+		}
+	}
+	sort.Strings(std)
+	sort.Strings(other)
+
+	fmt.Fprintf(f, "import (\n")
+	for i, group := range [][]string{std, other} {
+		if i > 0 && len(std) > 0 && len(other) > 0 {
+			fmt.Fprintf(f, "\n")
+		}
+		for _, path := range group {
+			if imp := imports[path]; imp.name != imp.pkgName {
+				fmt.Fprintf(f, "\t%s %q\n", imp.name, path)
+			} else {
+				fmt.Fprintf(f, "\t%q\n", path)
+			}
+		}
+	}
+	fmt.Fprintf(f, ")\n\n")
+}
+
+// writeConverters emits To<T> and From<T> so handlers can work with the struct
+// the developer declared instead of the randomized Binded one.
+func writeConverters(f io.Writer, t string, fields []fieldInfo) {
+	fmt.Fprintf(f, "// To%s converts the bound form values into a %s.\n", t, t)
+	fmt.Fprintf(f, "func (b *Binded%s) To%s() %s {\n", t, t, t)
+	fmt.Fprintf(f, "\tvar v %s\n", t)
+	for _, field := range fields {
+		src, dst := "b."+field.bindedName(), "v."+field.FieldName
+		switch {
+		case field.Kind == fieldNested && strings.HasPrefix(field.FieldType, "*"):
+			fmt.Fprintf(f, "\tif %s != nil {\n", src)
+			fmt.Fprintf(f, "\t\tx := %s.To%s()\n", src, field.Elem)
+			fmt.Fprintf(f, "\t\t%s = &x\n", dst)
+			fmt.Fprintf(f, "\t}\n")
+		case field.Kind == fieldNested, field.Kind == fieldEmbedded:
+			fmt.Fprintf(f, "\t%s = %s.To%s()\n", dst, src, field.Elem)
+		case field.Kind == fieldList && strings.HasPrefix(field.FieldType, "[]*"):
+			fmt.Fprintf(f, "\tif %s != nil {\n", src)
+			fmt.Fprintf(f, "\t\t%s = make([]*%s, len(%s))\n", dst, field.Elem, src)
+			fmt.Fprintf(f, "\t\tfor i, item := range %s {\n", src)
+			fmt.Fprintf(f, "\t\t\tif item != nil {\n")
+			fmt.Fprintf(f, "\t\t\t\tx := item.To%s()\n", field.Elem)
+			fmt.Fprintf(f, "\t\t\t\t%s[i] = &x\n", dst)
+			fmt.Fprintf(f, "\t\t\t}\n")
+			fmt.Fprintf(f, "\t\t}\n")
+			fmt.Fprintf(f, "\t}\n")
+		case field.Kind == fieldList:
+			fmt.Fprintf(f, "\tif %s != nil {\n", src)
+			fmt.Fprintf(f, "\t\t%s = make([]%s, len(%s))\n", dst, field.Elem, src)
+			fmt.Fprintf(f, "\t\tfor i := range %s {\n", src)
+			fmt.Fprintf(f, "\t\t\t%s[i] = %s[i].To%s()\n", dst, src, field.Elem)
+			fmt.Fprintf(f, "\t\t}\n")
+			fmt.Fprintf(f, "\t}\n")
+		default:
+			fmt.Fprintf(f, "\t%s = %s\n", dst, src)
+		}
+	}
+	fmt.Fprintf(f, "\treturn v\n")
+	fmt.Fprintf(f, "}\n\n")
+
+	fmt.Fprintf(f, "// From%s fills the bound struct from a %s, e.g. to pre-fill a form.\n", t, t)
+	fmt.Fprintf(f, "func (b *Binded%s) From%s(v %s) {\n", t, t, t)
+	for _, field := range fields {
+		src, dst := "v."+field.FieldName, "b."+field.bindedName()
+		binded := "Binded" + field.Elem
+		switch {
+		case field.Kind == fieldNested && strings.HasPrefix(field.FieldType, "*"):
+			fmt.Fprintf(f, "\t%s = nil\n", dst)
+			fmt.Fprintf(f, "\tif %s != nil {\n", src)
+			fmt.Fprintf(f, "\t\t%s = new(%s)\n", dst, binded)
+			fmt.Fprintf(f, "\t\t%s.From%s(*%s)\n", dst, field.Elem, src)
+			fmt.Fprintf(f, "\t}\n")
+		case field.Kind == fieldNested, field.Kind == fieldEmbedded:
+			fmt.Fprintf(f, "\t%s.From%s(%s)\n", dst, field.Elem, src)
+		case field.Kind == fieldList && strings.HasPrefix(field.FieldType, "[]*"):
+			fmt.Fprintf(f, "\t%s = nil\n", dst)
+			fmt.Fprintf(f, "\tif %s != nil {\n", src)
+			fmt.Fprintf(f, "\t\t%s = make([]*%s, len(%s))\n", dst, binded, src)
+			fmt.Fprintf(f, "\t\tfor i, item := range %s {\n", src)
+			fmt.Fprintf(f, "\t\t\tif item != nil {\n")
+			fmt.Fprintf(f, "\t\t\t\t%s[i] = new(%s)\n", dst, binded)
+			fmt.Fprintf(f, "\t\t\t\t%s[i].From%s(*item)\n", dst, field.Elem)
+			fmt.Fprintf(f, "\t\t\t}\n")
+			fmt.Fprintf(f, "\t\t}\n")
+			fmt.Fprintf(f, "\t}\n")
+		case field.Kind == fieldList:
+			fmt.Fprintf(f, "\t%s = nil\n", dst)
+			fmt.Fprintf(f, "\tif %s != nil {\n", src)
+			fmt.Fprintf(f, "\t\t%s = make([]%s, len(%s))\n", dst, binded, src)
+			fmt.Fprintf(f, "\t\tfor i := range %s {\n", src)
+			fmt.Fprintf(f, "\t\t\t%s[i].From%s(%s[i])\n", dst, field.Elem, src)
+			fmt.Fprintf(f, "\t\t}\n")
+			fmt.Fprintf(f, "\t}\n")
+		default:
+			fmt.Fprintf(f, "\t%s = %s\n", dst, src)
+		}
+	}
+	fmt.Fprintf(f, "}\n\n")
+}
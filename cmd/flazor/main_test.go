@@ -0,0 +1,179 @@
+package main
+
+import (
+	"bytes"
+	"go/format"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// writeTree writes files (slash-separated paths relative to dir) for a test project.
+func writeTree(t *testing.T, dir string, files map[string]string) {
+	t.Helper()
+	for name, content := range files {
+		path := filepath.Join(dir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func readFile(t *testing.T, path string) string {
+	t.Helper()
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(data)
+}
+
+const testGoMod = "module example.com/app\n\ngo 1.25\n"
+
+func testConfig(t *testing.T, dir string) config {
+	t.Helper()
+	cfg, err := loadConfig(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cfg.Suffix = suffixConfig{Strategy: suffixDeterministic, Salt: "test"}
+	return cfg
+}
+
+func TestGenerateBindersPerPackage(t *testing.T) {
+	dir := t.TempDir()
+	writeTree(t, dir, map[string]string{
+		"go.mod": testGoMod,
+		"main.go": `package main
+
+//blazor:bind
+type Login struct {
+	User string ` + "`form:\"user\" validate:\"required\"`" + `
+	Pass string ` + "`form:\"pass\"`" + `
+	Next string ` + "`form:\"-\"`" + `
+}
+
+func main() {}
+`,
+		"forms/signup.go": `package forms
+
+//blazor:bind
+type Signup struct {
+	Email, Name string
+	Age         int ` + "`form:\"age\" validate:\"min=18\"`" + `
+}
+`,
+		"plain/plain.go":   "package plain\n\ntype Other struct{ A int }\n",
+		"plain/old_gen.go": genHeader + "\n\npackage plain\n",
+	})
+
+	if err := generateBinders(dir, testConfig(t, dir), nil); err != nil {
+		t.Fatal(err)
+	}
+
+	for path, want := range map[string][]string{
+		"blazor_gen.go":       {"package main", "type BindedLogin struct", "func GetBindingOfLogin() BindingOfLogin"},
+		"forms/blazor_gen.go": {"package forms", "type BindedSignup struct", "bind_Signup_Email", "bind_Signup_Name"},
+	} {
+		src := readFile(t, filepath.Join(dir, path))
+		for _, w := range want {
+			if !strings.Contains(src, w) {
+				t.Errorf("Expected %q in %s:\n%s", w, path, src)
+			}
+		}
+		formatted, err := format.Source([]byte(src))
+		if err != nil || !bytes.Equal(formatted, []byte(src)) {
+			t.Errorf("Expected %s to be gofmt-clean (%v)", path, err)
+		}
+	}
+
+	if src := readFile(t, filepath.Join(dir, genFileName)); strings.Contains(src, "Next") {
+		t.Errorf("Expected no binder field for form:\"-\":\n%s", src)
+	}
+
+	if _, err := os.Stat(filepath.Join(dir, "plain", genFileName)); !os.IsNotExist(err) {
+		t.Errorf("Expected no %s for a package without bind structs", genFileName)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "plain", "old_gen.go")); !os.IsNotExist(err) {
+		t.Errorf("Expected the stale generated file to be removed")
+	}
+
+	// Only the packages in dirs are regenerated.
+	os.Remove(filepath.Join(dir, genFileName))
+	os.Remove(filepath.Join(dir, "forms", genFileName))
+	if err := generateBinders(dir, testConfig(t, dir), map[string]bool{"forms": true}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, genFileName)); !os.IsNotExist(err) {
+		t.Errorf("Expected the root package to be left alone")
+	}
+	if _, err := os.Stat(filepath.Join(dir, "forms", genFileName)); err != nil {
+		t.Errorf("Expected forms to be regenerated: %v", err)
+	}
+}
+
+func TestGenerateBindersReportsProblems(t *testing.T) {
+	dir := t.TempDir()
+	writeTree(t, dir, map[string]string{
+		"go.mod":      testGoMod,
+		"main.go":     "package main\n\nfunc main() {}\n",
+		"broken/a.go": "package broken\n\nfunc (\n",
+		"shapes/shapes.go": `package shapes
+
+import "time"
+
+//blazor:bind
+type Circle struct {
+	Radius float64
+	Draw   func()
+}
+
+//blazor:bind
+type Name string
+
+//blazor:bind
+type Labels struct {
+	Counts map[string]int
+	Times  map[string]time.Time
+	Tags   map[string][]string
+	Points map[string]Point
+}
+
+type Point struct{ X, Y int }
+
+//blazor:bind
+type Pair struct {
+	A, B int ` + "`form:\"ab\"`" + `
+	C, D int ` + "`form:\",omitempty\" validate:\"min=1\"`" + `
+}
+`,
+	})
+
+	err := generateBinders(dir, testConfig(t, dir), nil)
+	if err == nil {
+		t.Fatal("Expected an error")
+	}
+	for _, want := range []string{
+		filepath.Join("broken", "a.go") + ":3:",
+		filepath.Join(dir, "shapes", "shapes.go") + ":8:2: Circle.Draw: func fields cannot be bound from a form",
+		filepath.Join(dir, "shapes", "shapes.go") + ":12:6: Name: //blazor:bind only applies to struct types",
+		filepath.Join(dir, "shapes", "shapes.go") + ":17:2: Labels.Times: map values must be strings, bools or numbers, got time.Time",
+		filepath.Join(dir, "shapes", "shapes.go") + ":18:2: Labels.Tags: map values must be strings, bools or numbers, got []string",
+		filepath.Join(dir, "shapes", "shapes.go") + ":19:2: Labels.Points: map values must be strings, bools or numbers, got shapes.Point",
+		filepath.Join(dir, "shapes", "shapes.go") + `:26:2: Pair.A, B: fields declared together share the name in form:"ab"; declare them separately`,
+	} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("Expected %q in:\n%v", want, err)
+		}
+	}
+	if strings.Contains(err.Error(), "Pair.C") || strings.Contains(err.Error(), "Labels.Counts") {
+		t.Errorf("Expected Pair.C and Labels.Counts to be accepted:\n%v", err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "shapes", genFileName)); !os.IsNotExist(err) {
+		t.Errorf("Expected nothing to be written when a package has problems")
+	}
+}
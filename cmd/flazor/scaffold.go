@@ -0,0 +1,228 @@
+package main
+
+import (
+	"bytes"
+	"embed"
+	"errors"
+	"flag"
+	"fmt"
+	"go/parser"
+	"go/token"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"text/template"
+	"unicode"
+)
+
+// Templates use [[ ]] delimiters so templ's own {{ }} blocks pass through untouched.
+//
+//go:embed templates
+var scaffoldFS embed.FS
+
+// scaffoldData is passed to every scaffold template.
+type scaffoldData struct {
+	Module  string
+	Package string
+	Name    string // exported Go name, e.g. TodoList
+	Lower   string // todoList
+	Snake   string // todo_list
+	Kebab   string // todo-list
+	Salt    string
+}
+
+// scaffoldFile maps an embedded template to the file it produces.
+type scaffoldFile struct {
+	template string
+	output   string
+}
+
+var exportedName = regexp.MustCompile(`^[A-Z][A-Za-z0-9]*$`)
+
+// runNew implements `flazor new <module>`, creating a runnable app in a directory
+// named after the last element of the module path.
+func runNew(args []string) error {
+	flags := flag.NewFlagSet("new", flag.ContinueOnError)
+	dir := flags.String("dir", "", "directory to create (default: last element of the module path)")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if flags.NArg() != 1 {
+		return fmt.Errorf("usage: flazor new [-dir path] <module>")
+	}
+	module := flags.Arg(0)
+	if *dir == "" {
+		*dir = path.Base(module)
+	}
+
+	data := scaffoldData{Module: module, Package: "main", Name: path.Base(module), Salt: randomString(16)}
+	files := []scaffoldFile{
+		{"templates/new/go.mod.tmpl", "go.mod"},
+		{"templates/new/flazor.json.tmpl", configFile},
+		{"templates/new/main.go.tmpl", "main.go"},
+		{"templates/new/greet.go.tmpl", "greet.go"},
+		{"templates/new/home.templ.tmpl", "home.templ"},
+	}
+	if err := writeScaffold(*dir, files, data); err != nil {
+		return err
+	}
+
+	fmt.Printf("\nNext steps:\n  cd %s\n  go mod tidy\n  flazor\n  go run .\n", *dir)
+	return nil
+}
+
+// runAdd implements `flazor add component <Name>` and `flazor add page <Name>`.
+func runAdd(args []string) error {
+	if len(args) == 0 {
+		return fmt.Errorf("usage: flazor add component|page [-dir path] <Name>")
+	}
+	kind := args[0]
+
+	flags := flag.NewFlagSet("add "+kind, flag.ContinueOnError)
+	dir := flags.String("dir", ".", "package directory to add the files to")
+	if err := flags.Parse(args[1:]); err != nil {
+		return err
+	}
+	if flags.NArg() != 1 {
+		return fmt.Errorf("usage: flazor add %s [-dir path] <Name>", kind)
+	}
+	name := flags.Arg(0)
+	if !exportedName.MatchString(name) {
+		return fmt.Errorf("%q is not an exported Go identifier (e.g. TodoList)", name)
+	}
+
+	pkg, err := packageName(*dir)
+	if err != nil {
+		return err
+	}
+	data := scaffoldData{
+		Package: pkg,
+		Name:    name,
+		Lower:   lowerCamel(name),
+		Snake:   joinWords(name, "_"),
+		Kebab:   joinWords(name, "-"),
+	}
+
+	var files []scaffoldFile
+	var next string
+	switch kind {
+	case "component":
+		files = []scaffoldFile{
+			{"templates/component/component.go.tmpl", data.Snake + ".go"},
+			{"templates/component/component.templ.tmpl", data.Snake + ".templ"},
+		}
+		next = fmt.Sprintf("%sEndpoint = router.Post(\"/%s\", %sHandler())", data.Lower, data.Kebab, name)
+	case "page":
+		files = []scaffoldFile{
+			{"templates/page/page.go.tmpl", data.Snake + "_page.go"},
+			{"templates/page/page.templ.tmpl", data.Snake + "_page.templ"},
+		}
+		next = fmt.Sprintf("app.Get(\"/%s\", %sPageHandler())", data.Kebab, name)
+	default:
+		return fmt.Errorf("unknown kind %q: want component or page", kind)
+	}
+
+	if err := writeScaffold(*dir, files, data); err != nil {
+		return err
+	}
+	fmt.Printf("\nRegister the route, then run flazor:\n  %s\n", next)
+	return nil
+}
+
+// writeScaffold renders files into dir. It checks every target first and writes
+// nothing if any of them already exists.
+func writeScaffold(dir string, files []scaffoldFile, data scaffoldData) error {
+	rendered := make([][]byte, len(files))
+	for i, file := range files {
+		target := filepath.Join(dir, file.output)
+		if _, err := os.Stat(target); err == nil {
+			return fmt.Errorf("%s already exists, refusing to overwrite", target)
+		} else if !errors.Is(err, os.ErrNotExist) {
+			return err
+		}
+
+		tmpl, err := template.New(path.Base(file.template)).Delims("[[", "]]").ParseFS(scaffoldFS, file.template)
+		if err != nil {
+			return err
+		}
+		var buf bytes.Buffer
+		if err := tmpl.Execute(&buf, data); err != nil {
+			return err
+		}
+		rendered[i] = buf.Bytes()
+	}
+
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return err
+	}
+	for i, file := range files {
+		target := filepath.Join(dir, file.output)
+		// O_EXCL guards against a file appearing between the check and the write.
+		f, err := os.OpenFile(target, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0644)
+		if err != nil {
+			return err
+		}
+		_, err = f.Write(rendered[i])
+		if closeErr := f.Close(); err == nil {
+			err = closeErr
+		}
+		if err != nil {
+			return err
+		}
+		fmt.Printf("Created %s\n", target)
+	}
+	return nil
+}
+
+// packageName returns the package declared by the non-test .go files in dir,
+// or "main" when there are none.
+func packageName(dir string) (string, error) {
+	matches, err := filepath.Glob(filepath.Join(dir, "*.go"))
+	if err != nil {
+		return "", err
+	}
+	for _, match := range matches {
+		if strings.HasSuffix(match, "_test.go") {
+			continue
+		}
+		f, err := parser.ParseFile(token.NewFileSet(), match, nil, parser.PackageClauseOnly)
+		if err != nil {
+			continue
+		}
+		return f.Name.Name, nil
+	}
+	return "main", nil
+}
+
+// joinWords splits a CamelCase name into lowercase words joined by sep,
+// keeping acronyms together: HTTPServer -> http_server.
+func joinWords(name, sep string) string {
+	var sb strings.Builder
+	runes := []rune(name)
+	for i, r := range runes {
+		if i > 0 && unicode.IsUpper(r) {
+			prevLower := unicode.IsLower(runes[i-1]) || unicode.IsDigit(runes[i-1])
+			nextLower := i+1 < len(runes) && unicode.IsLower(runes[i+1])
+			if prevLower || (unicode.IsUpper(runes[i-1]) && nextLower) {
+				sb.WriteString(sep)
+			}
+		}
+		sb.WriteRune(unicode.ToLower(r))
+	}
+	return sb.String()
+}
+
+// lowerCamel lowercases the leading word of name, including an acronym:
+// TodoList -> todoList, HTTPStatus -> httpStatus.
+func lowerCamel(name string) string {
+	runes := []rune(name)
+	for i := range runes {
+		if !unicode.IsUpper(runes[i]) || (i > 0 && i+1 < len(runes) && unicode.IsLower(runes[i+1])) {
+			break
+		}
+		runes[i] = unicode.ToLower(runes[i])
+	}
+	return string(runes)
+}
@@ -0,0 +1,153 @@
+package main
+
+import (
+	"go/parser"
+	"go/token"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestRunNew(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "app")
+	if err := runNew([]string{"-dir", dir, "example.com/hello"}); err != nil {
+		t.Fatal(err)
+	}
+
+	for name, want := range map[string]string{
+		"go.mod":     "module example.com/hello\n",
+		configFile:   `"strategy": "deterministic"`,
+		"main.go":    "GreetError()",
+		"greet.go":   "//blazor:bind\ntype GreetRequest struct",
+		"home.templ": "templ GreetError()",
+	} {
+		src := readFile(t, filepath.Join(dir, name))
+		if !strings.Contains(src, want) {
+			t.Errorf("Expected %q in %s:\n%s", want, name, src)
+		}
+		if strings.HasSuffix(name, ".go") {
+			if _, err := parser.ParseFile(token.NewFileSet(), name, src, 0); err != nil {
+				t.Errorf("Expected %s to parse: %v", name, err)
+			}
+		}
+	}
+
+	cfg, err := loadConfig(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(cfg.Suffix.Salt) != 32 {
+		t.Errorf("Expected a salt of 16 random bytes, got %q", cfg.Suffix.Salt)
+	}
+
+	// A second run into the same directory must fail without touching anything.
+	if err := runNew([]string{"-dir", dir, "example.com/other"}); err == nil || !strings.Contains(err.Error(), "refusing to overwrite") {
+		t.Errorf("Expected a refusal to overwrite, got %v", err)
+	}
+	if got := readFile(t, filepath.Join(dir, "go.mod")); !strings.Contains(got, "example.com/hello") {
+		t.Errorf("Expected go.mod to be left alone, got:\n%s", got)
+	}
+
+	if err := runNew(nil); err == nil {
+		t.Errorf("Expected a usage error without a module")
+	}
+}
+
+func TestRunAdd(t *testing.T) {
+	dir := t.TempDir()
+	writeTree(t, dir, map[string]string{
+		"todo.go":      "package todo\n",
+		"todo_test.go": "package todo_test\n",
+	})
+
+	if err := runAdd([]string{"component", "-dir", dir, "TodoList"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := runAdd([]string{"page", "-dir", dir, "HTTPStatus"}); err != nil {
+		t.Fatal(err)
+	}
+
+	for name, want := range map[string][]string{
+		"todo_list.go":           {"package todo", "type TodoListRequest struct", "var todoListEndpoint blazor.Endpoint", `router.Post("/todo-list"`},
+		"todo_list.templ":        {"package todo", "templ TodoList(data TodoListData)", "GetBindingOfTodoListRequestFrom(ctx)"},
+		"http_status_page.go":    {"package todo", "func HTTPStatusPageHandler() fiber.Handler", `app.Get("/http-status"`},
+		"http_status_page.templ": {"package todo", "templ HTTPStatusPage()"},
+	} {
+		src := readFile(t, filepath.Join(dir, name))
+		for _, w := range want {
+			if !strings.Contains(src, w) {
+				t.Errorf("Expected %q in %s:\n%s", w, name, src)
+			}
+		}
+	}
+
+	// A directory without Go files gets package main.
+	empty := t.TempDir()
+	if err := runAdd([]string{"page", "-dir", empty, "About"}); err != nil {
+		t.Fatal(err)
+	}
+	if src := readFile(t, filepath.Join(empty, "about_page.go")); !strings.HasPrefix(src, "package main\n") {
+		t.Errorf("Expected package main, got:\n%s", src)
+	}
+
+	for _, tt := range []struct {
+		args []string
+		err  string
+	}{
+		{[]string{"component", "-dir", dir, "TodoList"}, "refusing to overwrite"},
+		{[]string{"component", "-dir", dir, "todoList"}, "not an exported Go identifier"},
+		{[]string{"widget", "-dir", dir, "Widget"}, `unknown kind "widget"`},
+		{[]string{"page", "-dir", dir}, "usage"},
+		{nil, "usage"},
+	} {
+		if err := runAdd(tt.args); err == nil || !strings.Contains(err.Error(), tt.err) {
+			t.Errorf("runAdd(%q): expected an error containing %q, got %v", tt.args, tt.err, err)
+		}
+	}
+}
+
+func TestWriteScaffoldRefusesToOverwrite(t *testing.T) {
+	dir := t.TempDir()
+	writeTree(t, dir, map[string]string{"b.templ": "keep\n"})
+	files := []scaffoldFile{
+		{"templates/page/page.go.tmpl", "a.go"},
+		{"templates/page/page.templ.tmpl", "b.templ"},
+	}
+
+	err := writeScaffold(dir, files, scaffoldData{Package: "main", Name: "A"})
+	if err == nil || !strings.Contains(err.Error(), filepath.Join(dir, "b.templ")+" already exists") {
+		t.Fatalf("Expected a refusal naming b.templ, got %v", err)
+	}
+	// Nothing is written, not even the files that did not exist yet.
+	if _, err := os.Stat(filepath.Join(dir, "a.go")); !os.IsNotExist(err) {
+		t.Errorf("Expected a.go not to be written")
+	}
+	if got := readFile(t, filepath.Join(dir, "b.templ")); got != "keep\n" {
+		t.Errorf("Expected b.templ to be left alone, got %q", got)
+	}
+}
+
+func TestNameCase(t *testing.T) {
+	for _, tt := range []struct {
+		name, snake, kebab, lower string
+	}{
+		{"TodoList", "todo_list", "todo-list", "todoList"},
+		{"HTTPServer", "http_server", "http-server", "httpServer"},
+		{"HTTPStatus", "http_status", "http-status", "httpStatus"},
+		{"ID", "id", "id", "id"},
+		{"Page2Go", "page2_go", "page2-go", "page2Go"},
+		{"UserID", "user_id", "user-id", "userID"},
+		{"A", "a", "a", "a"},
+	} {
+		if got := joinWords(tt.name, "_"); got != tt.snake {
+			t.Errorf("joinWords(%q, _) = %q, want %q", tt.name, got, tt.snake)
+		}
+		if got := joinWords(tt.name, "-"); got != tt.kebab {
+			t.Errorf("joinWords(%q, -) = %q, want %q", tt.name, got, tt.kebab)
+		}
+		if got := lowerCamel(tt.name); got != tt.lower {
+			t.Errorf("lowerCamel(%q) = %q, want %q", tt.name, got, tt.lower)
+		}
+	}
+}
@@ -0,0 +1,207 @@
+package main
+
+import (
+	"fmt"
+	"go/ast"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// defaultRewriteTags are the struct tags that carry a bound name, in the order
+// blazor.FieldKey reads them. Only these get the randomized suffix.
+var defaultRewriteTags = []string{"form", "query", "header", "cookie"}
+
+type tagPair struct {
+	key   string
+	value string
+}
+
+// structTag is a parsed struct tag that keeps the original key order.
+type structTag []tagPair
+
+// parseTag parses a field's tag literal with the same rules as reflect.StructTag.
+func parseTag(lit *ast.BasicLit) (structTag, error) {
+	if lit == nil {
+		return nil, nil
+	}
+	raw, err := strconv.Unquote(lit.Value)
+	if err != nil {
+		return nil, fmt.Errorf("malformed struct tag %s", lit.Value)
+	}
+
+	var tag structTag
+	for raw = strings.TrimLeft(raw, " "); raw != ""; raw = strings.TrimLeft(raw, " ") {
+		i := 0
+		for i < len(raw) && raw[i] > ' ' && raw[i] != ':' && raw[i] != '"' && raw[i] != 0x7f {
+			i++
+		}
+		if i == 0 || i+1 >= len(raw) || raw[i] != ':' || raw[i+1] != '"' {
+			return nil, fmt.Errorf("malformed struct tag %s", lit.Value)
+		}
+		key := raw[:i]
+		raw = raw[i+1:]
+
+		// Scan the quoted value, honouring escapes.
+		i = 1
+		for i < len(raw) && raw[i] != '"' {
+			if raw[i] == '\\' {
+				i++
+			}
+			i++
+		}
+		if i >= len(raw) {
+			return nil, fmt.Errorf("malformed struct tag %s", lit.Value)
+		}
+		value, err := strconv.Unquote(raw[:i+1])
+		if err != nil {
+			return nil, fmt.Errorf("malformed struct tag %s", lit.Value)
+		}
+		raw = raw[i+1:]
+		tag = append(tag, tagPair{key: key, value: value})
+	}
+	return tag, nil
+}
+
+// has reports whether any of keys is present.
+func (t structTag) has(keys []string) bool {
+	for _, pair := range t {
+		if slices.Contains(keys, pair.key) {
+			return true
+		}
+	}
+	return false
+}
+
+// name returns the bound name: the name part of the first tag in keys, or the
+// lowercased field name when there is none.
+func (t structTag) name(fieldName string, keys []string) string {
+	if pair, ok := t.explicit(keys); ok {
+		name, _, _ := strings.Cut(pair.value, ",")
+		return name
+	}
+	return strings.ToLower(fieldName)
+}
+
+// explicit returns the first tag in keys that sets a name, such as form:"ab".
+func (t structTag) explicit(keys []string) (tagPair, bool) {
+	for _, key := range keys {
+		for _, pair := range t {
+			if pair.key != key {
+				continue
+			}
+			if name, _, _ := strings.Cut(pair.value, ","); name != "" && name != "-" {
+				return pair, true
+			}
+		}
+	}
+	return tagPair{}, false
+}
+
+// skipped reports whether the field is excluded from binding with "-", as in
+// form:"-", and no other tag in keys names it. reflect and Fiber's decoder never
+// fill such a field, so it gets no binder field either.
+func (t structTag) skipped(keys []string) bool {
+	if _, ok := t.explicit(keys); ok {
+		return false
+	}
+	for _, pair := range t {
+		if slices.Contains(keys, pair.key) && pair.value == "-" {
+			return true
+		}
+	}
+	return false
+}
+
+// rewrite appends suffix to the name part of every tag in keys, keeping options
+// such as ",omitempty". Other tags are copied verbatim. Without any of keys a
+// form tag is added so the Binded struct decodes the name the binder renders.
+func (t structTag) rewrite(fieldName string, keys []string, suffix string, addForm bool) structTag {
+	out := make(structTag, 0, len(t)+1)
+	for _, pair := range t {
+		if !slices.Contains(keys, pair.key) {
+			out = append(out, pair)
+			continue
+		}
+		name, options, hasOptions := strings.Cut(pair.value, ",")
+		if name == "-" && !hasOptions {
+			out = append(out, pair) // explicitly not bound
+			continue
+		}
+		if name == "" {
+			name = strings.ToLower(fieldName)
+		}
+		value := name + "_" + suffix
+		if hasOptions {
+			value += "," + options
+		}
+		out = append(out, tagPair{key: pair.key, value: value})
+	}
+	if addForm && !t.has(keys) {
+		out = append(out, tagPair{key: "form", value: strings.ToLower(fieldName) + "_" + suffix})
+	}
+	return out
+}
+
+// String formats the tag as a Go literal, or "" for an empty tag.
+func (t structTag) String() string {
+	if len(t) == 0 {
+		return ""
+	}
+	parts := make([]string, len(t))
+	for i, pair := range t {
+		parts[i] = pair.key + ":" + strconv.Quote(pair.value)
+	}
+	content := strings.Join(parts, " ")
+	if strings.Contains(content, "`") {
+		return strconv.Quote(content)
+	}
+	return "`" + content + "`"
+}
+
+// accept returns the quoted entries of the validate tag's mime rule, which
+// become the accept attribute of a generated file field.
+func (t structTag) accept() []string {
+	for _, pair := range t {
+		if pair.key != "validate" {
+			continue
+		}
+		for _, rule := range strings.Split(pair.value, ",") {
+			if strings.HasPrefix(strings.TrimSpace(rule), "regex=") {
+				break // the pattern may contain commas; it is always the last rule
+			}
+			param, ok := strings.CutPrefix(strings.TrimSpace(rule), "mime=")
+			if !ok {
+				continue
+			}
+			var accept []string
+			for _, entry := range strings.Split(param, "|") {
+				if entry = strings.TrimSpace(entry); entry != "" {
+					accept = append(accept, strconv.Quote(entry))
+				}
+			}
+			return accept
+		}
+	}
+	return nil
+}
+
+// get returns the value of the key tag, or "".
+func (t structTag) get(key string) string {
+	for _, pair := range t {
+		if pair.key == key {
+			return pair.value
+		}
+	}
+	return ""
+}
+
+// hasRule reports whether the validate tag contains the parameterless rule.
+func (t structTag) hasRule(rule string) bool {
+	for _, r := range strings.Split(t.get("validate"), ",") {
+		if strings.TrimSpace(r) == rule {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,137 @@
+package main
+
+import (
+	"go/ast"
+	"go/token"
+	"reflect"
+	"strconv"
+	"testing"
+)
+
+func tagLit(raw string) *ast.BasicLit {
+	return &ast.BasicLit{Kind: token.STRING, Value: strconv.Quote(raw)}
+}
+
+func TestParseTagMatchesReflect(t *testing.T) {
+	for _, raw := range []string{
+		``,
+		`form:"name"`,
+		`form:"name,omitempty" validate:"required,min=1"`,
+		`json:"-" form:"-"`,
+		`form:"a\"b" label:"Say \"hi\""`,
+		`validate:"regex=^[a-z,]+$"   form:"x"`,
+		`uri:"id" form:"id"`,
+		`form:"é" label:"é\t"`,
+	} {
+		tag, err := parseTag(tagLit(raw))
+		if err != nil {
+			t.Errorf("parseTag(%q): %v", raw, err)
+			continue
+		}
+		st := reflect.StructTag(raw)
+		for _, pair := range tag {
+			if want, ok := st.Lookup(pair.key); !ok || want != pair.value {
+				t.Errorf("parseTag(%q): %s = %q, reflect says %q (%v)", raw, pair.key, pair.value, want, ok)
+			}
+		}
+		// Formatting the parsed tag must not change what reflect reads.
+		out, err := strconv.Unquote(tag.String())
+		if raw != "" && err != nil {
+			t.Errorf("String(%q) = %s is not a Go string: %v", raw, tag.String(), err)
+			continue
+		}
+		for _, pair := range tag {
+			if got := reflect.StructTag(out).Get(pair.key); got != pair.value {
+				t.Errorf("String(%q): %s = %q after formatting, want %q", raw, pair.key, got, pair.value)
+			}
+		}
+	}
+
+	for _, raw := range []string{`form`, `form:name`, `form:"name`, `:"x"`, `form:"a" b`} {
+		if _, err := parseTag(tagLit(raw)); err == nil {
+			t.Errorf("parseTag(%q): expected a malformed tag error", raw)
+		}
+	}
+}
+
+func TestRewriteTag(t *testing.T) {
+	keys := defaultRewriteTags
+	for _, tt := range []struct {
+		raw   string
+		field string
+		want  map[string]string // key -> value reflect reads after the rewrite
+	}{
+		{`form:"name"`, "Name", map[string]string{"form": "name_x1"}},
+		{`form:"name,omitempty" query:"q"`, "Name", map[string]string{"form": "name_x1,omitempty", "query": "q_x1"}},
+		{`form:",omitempty"`, "Email", map[string]string{"form": "email_x1,omitempty"}},
+		{`form:"-"`, "Secret", map[string]string{"form": "-"}},
+		{`form:"a\"b" label:"Say \"hi\""`, "Q", map[string]string{"form": `a"b_x1`, "label": `Say "hi"`}},
+		// uri is not one of the rewritten tags: path parameters keep their names,
+		// and the Binded struct still gets a form tag for the binder.
+		{`uri:"id"`, "ID", map[string]string{"uri": "id", "form": "id_x1"}},
+		{`validate:"required"`, "Title", map[string]string{"validate": "required", "form": "title_x1"}},
+	} {
+		tag, err := parseTag(tagLit(tt.raw))
+		if err != nil {
+			t.Fatal(err)
+		}
+		out, err := strconv.Unquote(tag.rewrite(tt.field, keys, "x1", true).String())
+		if err != nil {
+			t.Fatalf("rewrite(%q): %v", tt.raw, err)
+		}
+		st := reflect.StructTag(out)
+		for key, want := range tt.want {
+			if got := st.Get(key); got != want {
+				t.Errorf("rewrite(%q): %s = %q, want %q (tag %s)", tt.raw, key, got, want, out)
+			}
+		}
+	}
+
+	// Promoted embedded structs stay untagged.
+	if got := structTag(nil).rewrite("Address", keys, "x1", false).String(); got != "" {
+		t.Errorf("Expected no tag for an embedded struct, got %s", got)
+	}
+}
+
+func TestTagName(t *testing.T) {
+	for _, tt := range []struct {
+		raw, field, want string
+	}{
+		{`form:"user"`, "Name", "user"},
+		{`query:"q" form:"f"`, "Name", "f"}, // form comes first in defaultRewriteTags
+		{`form:",omitempty" query:"q"`, "Name", "q"},
+		{`uri:"id"`, "ID", "id"},
+		{``, "CreatedAt", "createdat"},
+	} {
+		tag, err := parseTag(tagLit(tt.raw))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := tag.name(tt.field, defaultRewriteTags); got != tt.want {
+			t.Errorf("name(%q, %s) = %q, want %q", tt.raw, tt.field, got, tt.want)
+		}
+	}
+}
+
+func TestTagSkipped(t *testing.T) {
+	for _, tt := range []struct {
+		raw  string
+		want bool
+	}{
+		{`form:"-"`, true},
+		{`form:"-" json:"secret"`, true},
+		{`form:"-" query:"q"`, false}, // still bound from the query
+		{`form:"-,"`, false},          // a field named "-"
+		{`json:"-"`, false},           // not one of the bound tags
+		{`form:",omitempty"`, false},
+		{``, false},
+	} {
+		tag, err := parseTag(tagLit(tt.raw))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := tag.skipped(defaultRewriteTags); got != tt.want {
+			t.Errorf("skipped(%q) = %v, want %v", tt.raw, got, tt.want)
+		}
+	}
+}
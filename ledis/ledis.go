@@ -540,3 +540,25 @@ func (d *DistributedMap) TTL(key string) time.Duration {
 
 	return ttl
 }
+
+// Expire sets a timeout on key. A non-positive duration deletes the key, like Redis.
+// It returns false if the key does not exist.
+func (d *DistributedMap) Expire(key string, duration time.Duration) bool {
+	if duration <= 0 {
+		if !d.Exists(key) {
+			return false
+		}
+		d.Del(key)
+		return true
+	}
+
+	item, err := d.Get(key)
+	if err != nil {
+		return false
+	}
+
+	item.Mu.Lock()
+	item.ExpiresAt = time.Now().Add(duration).UnixNano()
+	item.Mu.Unlock()
+	return true
+}
@@ -56,6 +56,36 @@ func TestTTL(t *testing.T) {
 	}
 }
 
+func TestExpire(t *testing.T) {
+	db := New(16)
+
+	if db.Expire("missing", time.Second) {
+		t.Errorf("Expected Expire on a missing key to return false")
+	}
+
+	db.HSet("hash_ttl", "field", "value")
+	if ttl := db.TTL("hash_ttl"); ttl != -1 {
+		t.Errorf("Expected no expiration, got %v", ttl)
+	}
+	if !db.Expire("hash_ttl", 100*time.Millisecond) {
+		t.Fatalf("Expected Expire to return true")
+	}
+	if ttl := db.TTL("hash_ttl"); ttl <= 0 || ttl > 100*time.Millisecond {
+		t.Errorf("Unexpected TTL %v", ttl)
+	}
+
+	time.Sleep(200 * time.Millisecond)
+
+	if db.Exists("hash_ttl") {
+		t.Errorf("Expected hash_ttl to expire")
+	}
+
+	db.Set("key_del", "value", 0)
+	if !db.Expire("key_del", 0) || db.Exists("key_del") {
+		t.Errorf("Expected a zero duration to delete the key")
+	}
+}
+
 func TestConcurrency(t *testing.T) {
 	db := New(1024) // Larger size to reduce collision probability in sharding visualization if we were tracing
 
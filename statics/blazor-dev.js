@@ -0,0 +1,11 @@
+// Reloads the page when the dev server restarts (flazor dev).
+(function () {
+  var url = document.currentScript.getAttribute("data-url");
+  var build = null;
+  new EventSource(url).addEventListener("build", function (e) {
+    if (build !== null && build !== e.data) {
+      location.reload();
+    }
+    build = e.data;
+  });
+})();
@@ -0,0 +1,33 @@
+// Shows upload progress for requests whose element sets data-blazor-progress (HXAttr.Progress).
+(function () {
+  function update(e, loaded, total) {
+    var source = e.target.closest("[data-blazor-progress]");
+    if (!source) {
+      return;
+    }
+    var target = document.querySelector(source.getAttribute("data-blazor-progress"));
+    if (!target) {
+      return;
+    }
+    var percent = total > 0 ? Math.round((loaded / total) * 100) : 0;
+    if (target.tagName === "PROGRESS") {
+      target.max = 100;
+      target.value = percent;
+    } else {
+      target.style.setProperty("--blazor-progress", percent + "%");
+    }
+    target.dispatchEvent(new CustomEvent("blazor:progress", {
+      bubbles: true,
+      detail: { loaded: loaded, total: total, percent: percent },
+    }));
+  }
+
+  document.addEventListener("htmx:xhr:loadstart", function (e) {
+    update(e, 0, 0);
+  });
+  document.addEventListener("htmx:xhr:progress", function (e) {
+    if (e.detail.lengthComputable) {
+      update(e, e.detail.loaded, e.detail.total);
+    }
+  });
+})();
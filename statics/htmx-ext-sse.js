@@ -0,0 +1,159 @@
+/*
+ * Server-Sent Events extension for htmx 2.
+ *
+ * Supports the attributes of the official htmx-ext-sse package:
+ *   hx-ext="sse" sse-connect="/url"   open an EventSource on the element
+ *   sse-swap="event[,event]"          swap the event data into the element
+ *   hx-trigger="sse:event"            trigger an htmx request when the event arrives
+ *   sse-close="event"                 close the connection when the event arrives
+ */
+(function () {
+  var api;
+
+  function attr(elt, name) {
+    return api.getAttributeValue(elt, name);
+  }
+
+  function splitNames(value) {
+    return (value || "").split(",").map(function (s) { return s.trim(); }).filter(Boolean);
+  }
+
+  function sourceOf(elt) {
+    while (elt) {
+      var data = api.getInternalData(elt);
+      if (data.sseEventSource) {
+        return data.sseEventSource;
+      }
+      elt = elt.parentElement;
+    }
+    return null;
+  }
+
+  function listen(source, elt, name, handler) {
+    var data = api.getInternalData(elt);
+    data.sseListeners = data.sseListeners || [];
+    source.addEventListener(name, handler);
+    data.sseListeners.push({ source: source, name: name, handler: handler });
+  }
+
+  function bindSwap(elt) {
+    var source = sourceOf(elt);
+    if (!source) {
+      return;
+    }
+    splitNames(attr(elt, "sse-swap")).forEach(function (name) {
+      listen(source, elt, name, function (event) {
+        if (!document.body.contains(elt)) {
+          return;
+        }
+        if (!api.triggerEvent(elt, "htmx:sseBeforeMessage", event)) {
+          return;
+        }
+        htmx.swap(elt, event.data, api.getSwapSpecification(elt));
+        api.triggerEvent(elt, "htmx:sseMessage", event);
+      });
+    });
+  }
+
+  function bindTrigger(elt) {
+    var source = sourceOf(elt);
+    if (!source) {
+      return;
+    }
+    api.getTriggerSpecs(elt).forEach(function (spec) {
+      if (spec.trigger.slice(0, 4) !== "sse:") {
+        return;
+      }
+      listen(source, elt, spec.trigger.slice(4), function (event) {
+        if (document.body.contains(elt)) {
+          htmx.trigger(elt, spec.trigger, event);
+        }
+      });
+    });
+  }
+
+  function connect(elt, retry) {
+    var url = attr(elt, "sse-connect");
+    if (!url) {
+      return;
+    }
+    var source = new EventSource(url, { withCredentials: htmx.config.withCredentials });
+    api.getInternalData(elt).sseEventSource = source;
+
+    source.onopen = function () {
+      retry = 0;
+      api.triggerEvent(elt, "htmx:sseOpen", { source: source });
+    };
+    source.onerror = function (err) {
+      api.triggerErrorEvent(elt, "htmx:sseError", { error: err, source: source });
+      if (source.readyState !== EventSource.CLOSED || !document.body.contains(elt)) {
+        return;
+      }
+      // Back off up to a minute, then reconnect and rebind the listeners.
+      var delay = Math.min(1000 * Math.pow(2, retry), 60000);
+      setTimeout(function () {
+        connect(elt, retry + 1);
+        process(elt);
+        elt.querySelectorAll("[sse-swap], [data-sse-swap], [hx-trigger], [data-hx-trigger]").forEach(process);
+      }, delay);
+    };
+
+    splitNames(attr(elt, "sse-close")).forEach(function (name) {
+      source.addEventListener(name, function () {
+        source.close();
+        api.triggerEvent(elt, "htmx:sseClose", { source: source, type: "message" });
+      });
+    });
+  }
+
+  function unbind(elt) {
+    var data = api.getInternalData(elt);
+    (data.sseListeners || []).forEach(function (l) {
+      l.source.removeEventListener(l.name, l.handler);
+    });
+    data.sseListeners = [];
+  }
+
+  function process(elt) {
+    unbind(elt);
+    if (attr(elt, "sse-swap")) {
+      bindSwap(elt);
+    }
+    var trigger = attr(elt, "hx-trigger");
+    if (trigger && trigger.indexOf("sse:") >= 0) {
+      bindTrigger(elt);
+    }
+  }
+
+  function cleanup(elt) {
+    unbind(elt);
+    var data = api.getInternalData(elt);
+    if (data.sseEventSource) {
+      data.sseEventSource.close();
+      api.triggerEvent(elt, "htmx:sseClose", { source: data.sseEventSource, type: "nodeReplaced" });
+      data.sseEventSource = null;
+    }
+  }
+
+  htmx.defineExtension("sse", {
+    init: function (apiRef) {
+      api = apiRef;
+    },
+    getSelectors: function () {
+      return ["[sse-connect]", "[data-sse-connect]", "[sse-swap]", "[data-sse-swap]"];
+    },
+    onEvent: function (name, evt) {
+      var elt = evt.target || evt.detail.elt;
+      switch (name) {
+        case "htmx:beforeCleanupElement":
+          cleanup(elt);
+          return;
+        case "htmx:afterProcessNode":
+          if (attr(elt, "sse-connect") && !api.getInternalData(elt).sseEventSource) {
+            connect(elt, 0);
+          }
+          process(elt);
+      }
+    }
+  });
+})();
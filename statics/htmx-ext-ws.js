@@ -0,0 +1,158 @@
+/*
+ * WebSocket extension for htmx 2.
+ *
+ * Supports the attributes of the official htmx-ext-ws package:
+ *   hx-ext="ws" ws-connect="/url"   open a WebSocket on the element
+ *   ws-send                         send the element's values as JSON when it is triggered
+ *
+ * Outgoing messages carry the form values plus a HEADERS object with the usual
+ * htmx request headers (HX-Trigger-Name, HX-Target, hx-headers, ...).
+ * Every top-level element of an incoming message is swapped out-of-band by id.
+ */
+(function () {
+  var api;
+
+  function attr(elt, name) {
+    return api.getAttributeValue(elt, name);
+  }
+
+  function socketOwner(elt) {
+    while (elt) {
+      if (api.getInternalData(elt).webSocket) {
+        return elt;
+      }
+      elt = elt.parentElement;
+    }
+    return null;
+  }
+
+  function connect(elt, retry) {
+    var url = attr(elt, "ws-connect");
+    if (!url) {
+      return;
+    }
+    if (url.indexOf("/") === 0) {
+      var scheme = location.protocol === "https:" ? "wss://" : "ws://";
+      url = scheme + location.host + url;
+    }
+
+    var data = api.getInternalData(elt);
+    var socket = new WebSocket(url);
+    var queue = data.webSocket && data.webSocket.queue ? data.webSocket.queue : [];
+    data.webSocket = { socket: socket, queue: queue, closed: false };
+
+    socket.onopen = function () {
+      retry = 0;
+      api.triggerEvent(elt, "htmx:wsOpen", { socketWrapper: data.webSocket });
+      while (queue.length > 0 && socket.readyState === WebSocket.OPEN) {
+        socket.send(queue.shift());
+      }
+    };
+
+    socket.onmessage = function (event) {
+      if (!api.triggerEvent(elt, "htmx:wsBeforeMessage", { message: event.data })) {
+        return;
+      }
+      var settleInfo = api.makeSettleInfo(elt);
+      var fragment = api.makeFragment(event.data);
+      Array.prototype.slice.call(fragment.children).forEach(function (child) {
+        api.oobSwap(attr(child, "hx-swap-oob") || "true", child, settleInfo);
+      });
+      api.settleImmediately(settleInfo.tasks);
+      api.triggerEvent(elt, "htmx:wsAfterMessage", { message: event.data });
+    };
+
+    socket.onclose = function (event) {
+      api.triggerEvent(elt, "htmx:wsClose", { event: event });
+      if (data.webSocket.closed || !document.body.contains(elt)) {
+        return;
+      }
+      // Back off up to a minute before reconnecting; queued messages survive.
+      var delay = Math.min(1000 * Math.pow(2, retry), 60000);
+      setTimeout(function () {
+        connect(elt, retry + 1);
+      }, delay);
+    };
+
+    socket.onerror = function (err) {
+      api.triggerErrorEvent(elt, "htmx:wsError", { error: err });
+    };
+  }
+
+  function send(elt, evt) {
+    var owner = socketOwner(elt);
+    if (!owner) {
+      api.triggerErrorEvent(elt, "htmx:noWebSocketSourceError");
+      return;
+    }
+    if (evt && (elt.tagName === "FORM" || evt.type === "submit")) {
+      evt.preventDefault();
+    }
+    var formData = api.getInputValues(elt, "post").formData;
+    var target = api.getTarget(elt);
+    var headers = api.getHeaders(elt, target || owner);
+    var body = {};
+    formData.forEach(function (value, key) {
+      if (Object.prototype.hasOwnProperty.call(body, key)) {
+        body[key] = [].concat(body[key], value);
+      } else {
+        body[key] = value;
+      }
+    });
+    body.HEADERS = headers;
+
+    if (!api.triggerEvent(elt, "htmx:wsConfigSend", { parameters: body, headers: headers })) {
+      return;
+    }
+    var message = JSON.stringify(body);
+    var wrapper = api.getInternalData(owner).webSocket;
+    if (wrapper.socket.readyState === WebSocket.OPEN) {
+      wrapper.socket.send(message);
+    } else {
+      wrapper.queue.push(message);
+    }
+    api.triggerEvent(elt, "htmx:wsAfterSend", { message: message });
+  }
+
+  function bindSend(elt) {
+    var data = api.getInternalData(elt);
+    if (data.wsSendBound) {
+      return;
+    }
+    data.wsSendBound = true;
+    var specs = api.getTriggerSpecs(elt);
+    specs.forEach(function (spec) {
+      api.addTriggerHandler(elt, spec, data, function (_, evt) {
+        send(elt, evt);
+      });
+    });
+  }
+
+  htmx.defineExtension("ws", {
+    init: function (apiRef) {
+      api = apiRef;
+    },
+    getSelectors: function () {
+      return ["[ws-connect]", "[data-ws-connect]", "[ws-send]", "[data-ws-send]"];
+    },
+    onEvent: function (name, evt) {
+      var elt = evt.target || evt.detail.elt;
+      switch (name) {
+        case "htmx:beforeCleanupElement":
+          var data = api.getInternalData(elt);
+          if (data.webSocket) {
+            data.webSocket.closed = true;
+            data.webSocket.socket.close();
+          }
+          return;
+        case "htmx:afterProcessNode":
+          if (attr(elt, "ws-connect") && !api.getInternalData(elt).webSocket) {
+            connect(elt, 0);
+          }
+          if (api.hasAttribute(elt, "ws-send")) {
+            bindSend(elt);
+          }
+      }
+    }
+  });
+})();
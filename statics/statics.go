@@ -2,5 +2,10 @@ package statics
 
 import "embed"
 
+// htmx 확장은 htmx의 공식 배포본을 그대로 둡니다. 버전을 올릴 때는 아래 버전을 바꾸고 go generate를 실행합니다.
+// 배포본에는 라이선스 머리말이 없어서 버전과 라이선스(0BSD)를 첫 줄에 적습니다.
+//go:generate sh -c "curl -fsSL -o sse.tmp https://unpkg.com/htmx-ext-sse@2.2.2/sse.js || { rm -f sse.tmp; exit 1; }; { echo '/* htmx-ext-sse 2.2.2 | https://github.com/bigskysoftware/htmx-extensions | Zero-Clause BSD (0BSD) */'; cat sse.tmp; } > htmx-ext-sse.js && rm sse.tmp"
+//go:generate sh -c "curl -fsSL -o ws.tmp https://unpkg.com/htmx-ext-ws@2.0.2/ws.js || { rm -f ws.tmp; exit 1; }; { echo '/* htmx-ext-ws 2.0.2 | https://github.com/bigskysoftware/htmx-extensions | Zero-Clause BSD (0BSD) */'; cat ws.tmp; } > htmx-ext-ws.js && rm ws.tmp"
+
 //go:embed *.js
 var FS embed.FS
@@ -0,0 +1,56 @@
+// Code generated by blazor-gen. DO NOT EDIT.
+// flazor: suffix=deterministic
+
+package main
+
+import (
+	"context"
+
+	"github.com/snowmerak/fiber-blazor/blazor"
+)
+
+type BindedCalcRequest struct {
+	A int `form:"calc_a_a2749dd2" validate:"min=-1000000,max=1000000"`
+	B int `form:"calc_b_a2749dd2" validate:"min=-1000000,max=1000000"`
+}
+
+const (
+	bind_CalcRequest_A = "calc_a_a2749dd2"
+	bind_CalcRequest_B = "calc_b_a2749dd2"
+)
+
+// ToCalcRequest converts the bound form values into a CalcRequest.
+func (b *BindedCalcRequest) ToCalcRequest() CalcRequest {
+	var v CalcRequest
+	v.A = b.A
+	v.B = b.B
+	return v
+}
+
+// FromCalcRequest fills the bound struct from a CalcRequest, e.g. to pre-fill a form.
+func (b *BindedCalcRequest) FromCalcRequest(v CalcRequest) {
+	b.A = v.A
+	b.B = v.B
+}
+
+type BindingOfCalcRequest struct {
+	*blazor.Binding
+	A blazor.Field
+	B blazor.Field
+}
+
+func GetBindingOfCalcRequest() BindingOfCalcRequest {
+	return newBindingOfCalcRequest(blazor.NewBinding(""))
+}
+
+func GetBindingOfCalcRequestFrom(ctx context.Context) BindingOfCalcRequest {
+	return newBindingOfCalcRequest(blazor.BindingFrom(ctx))
+}
+
+func newBindingOfCalcRequest(b *blazor.Binding) BindingOfCalcRequest {
+	return BindingOfCalcRequest{
+		Binding: b,
+		A:       b.Field(bind_CalcRequest_A).Constrain(blazor.ValueInt, "min=-1000000,max=1000000"),
+		B:       b.Field(bind_CalcRequest_B).Constrain(blazor.ValueInt, "min=-1000000,max=1000000"),
+	}
+}
@@ -1,38 +1,81 @@
 package main
 
 import (
+	"context"
 	"log"
 
 	"github.com/a-h/templ"
 	"github.com/gofiber/fiber/v3"
 	"github.com/snowmerak/fiber-blazor/blazor"
+	"github.com/snowmerak/fiber-blazor/ledis"
 )
 
+const calcCountKey = "calc:count"
+
 //blazor:bind
 type CalcRequest struct {
-	A int `form:"calc_a"`
-	B int `form:"calc_b"`
+	A int `form:"calc_a" validate:"min=-1000000,max=1000000"`
+	B int `form:"calc_b" validate:"min=-1000000,max=1000000"`
 }
 
+// calculateEndpoint is assigned when main registers the route; the templates use it instead of a URL string.
+var calculateEndpoint blazor.Endpoint
+
 type CalcData struct {
+	A   int
+	B   int
 	Sum int
 }
 
 func main() {
 	app := fiber.New()
+	db := ledis.New(0)
+	defer db.Close()
 
 	blazor.Static(app, "/statics")
+	app.Use(blazor.Security(blazor.SecurityConfig{}))
+
+	layout := blazor.Layout{Title: "Fiber Blazor Calculator", Lang: "en", SSE: true}
+	app.Use(blazor.UseLayout(layout))
+
+	app.Get("/", blazor.InitLayout(Calculators(CalcData{}), layout))
 
-	app.Get("/", blazor.InitRender(Calculator(CalcData{}), "en", "Fiber Blazor Calculator"))
+	router := blazor.NewRouter(app)
 
-	app.Post("/calculate", blazor.SetRenderer(
+	calculateEndpoint = router.Post("/calculate", blazor.SetConvertedRenderer(
 		func(data *CalcData) templ.Component {
-			return Result(*data)
+			binder := GetBindingOfCalcRequest()
+			return blazor.Compose(Result(*data)).
+				OOB(binder.ID("last"), LastCalculation(*data))
 		},
-		func(req *BindedCalcRequest) (*CalcData, error) {
-			return &CalcData{Sum: req.A + req.B}, nil
+		(*BindedCalcRequest).ToCalcRequest,
+		func(req *CalcRequest) (*CalcData, error) {
+			if _, err := db.Incr(calcCountKey); err != nil {
+				return nil, err
+			}
+			return &CalcData{A: req.A, B: req.B, Sum: req.A + req.B}, nil
 		},
+		blazor.OnInvalid(func(c fiber.Ctx) templ.Component {
+			return CalcErrors()
+		}),
 	))
 
+	app.Get("/live/count", blazor.SSE(db, blazor.SSEConfig{
+		Keys: func(c fiber.Ctx) []string {
+			return []string{calcCountKey}
+		},
+		Render: func(ctx context.Context, ev blazor.LiveEvent) (templ.Component, error) {
+			item, err := db.Get(calcCountKey)
+			if err != nil {
+				return CalcCount("0"), nil
+			}
+			item.Mu.RLock()
+			defer item.Mu.RUnlock()
+			return CalcCount(item.Str), nil
+		},
+		Event:           "count",
+		RenderOnConnect: true,
+	}))
+
 	log.Fatal(app.Listen(":3000"))
 }
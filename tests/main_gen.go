@@ -1,30 +0,0 @@
-// Code generated by blazor-gen. DO NOT EDIT.
-package main
-
-import "github.com/snowmerak/fiber-blazor/blazor"
-
-type BindedCalcRequest struct {
-	A int `form:"calc_a_7089799b"`
-	B int `form:"calc_b_7089799b"`
-}
-
-const (
-	bind_CalcRequest_A = "calc_a_7089799b"
-	bind_CalcRequest_B = "calc_b_7089799b"
-)
-
-type BindingOfCalcRequest struct {
-	*blazor.Binding
-	A blazor.Field
-	B blazor.Field
-}
-
-func GetBindingOfCalcRequest() BindingOfCalcRequest {
-	b := blazor.NewBinding()
-	return BindingOfCalcRequest{
-		Binding: b,
-		A: b.Field(bind_CalcRequest_A),
-		B: b.Field(bind_CalcRequest_B),
-	}
-}
-